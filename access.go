@@ -3,66 +3,321 @@ package config_access
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
-func PathExists(path string, node ConfigNode) bool {
-	value := Value(path, node)
+// accessor implements the package's functions for accessing config values. Selectors use their own accessor so that
+// their options (e.g. the path separator) are applied to every access.
+type accessor struct {
+	pathSeparator string
+	converters    *Converters
+	timeLayouts   []string
+	coerce        bool
+	interpolate   bool
+	// Used instead of os.Getenv when expanding references, if set
+	envAccessFunc func(string) string
+	resolvers     []*Resolvers
+}
 
-	return value != nil
+var defaultAccessor = accessor{pathSeparator: PathSeparator}
+
+// reservedPathChars have a special meaning in paths, so cannot be used in a path separator
+const reservedPathChars = `[]"\`
+
+// validSeparator returns an InvalidSeparatorError if the supplied separator contains one of the reservedPathChars, as
+// every path would then be misread
+func validSeparator(separator string) error {
+
+	if strings.ContainsAny(separator, reservedPathChars) {
+		return InvalidSeparatorError{Separator: separator}
+	}
+
+	return nil
 }
 
-// Value returns the value at the supplied path or nil if the path does not exist of points to a null value.
-func Value(path string, node ConfigNode) interface{} {
+// newAccessor creates an accessor with the supplied options. It panics if the separator is not valid.
+func newAccessor(opts SelectorOpts) accessor {
 
-	if node == nil {
-		return nil
+	if err := validSeparator(opts.Separator); err != nil {
+		panic(err.Error())
+	}
+
+	a := accessor{pathSeparator: opts.Separator, converters: opts.Converters, timeLayouts: opts.TimeLayouts, coerce: opts.Coerce, interpolate: opts.Interpolate}
+
+	if opts.Resolvers != nil {
+		a.resolvers = []*Resolvers{opts.Resolvers}
+	}
+
+	return a
+}
+
+// withOpts returns a copy of the accessor that also applies the supplied per-call options
+func (a accessor) withOpts(opts Opts) accessor {
+
+	if opts.Coerce {
+		a.coerce = true
+	}
+
+	if opts.Interpolate {
+		a.interpolate = true
+	}
+
+	if opts.EnvAccessFunc != nil {
+		a.envAccessFunc = opts.EnvAccessFunc
 	}
 
-	splitPath := strings.Split(path, PathSeparator)
+	if opts.Resolvers != nil {
+		// Resolvers supplied for a call take precedence over those of the Selector
+		a.resolvers = append([]*Resolvers{opts.Resolvers}, a.resolvers...)
+	}
 
-	return configVal(splitPath, node)
+	return a
+}
+
+func (a accessor) separator() string {
+	if a.pathSeparator == "" {
+		return PathSeparator
+	}
 
+	return a.pathSeparator
+}
+
+func PathExists(path string, node ConfigNode) bool {
+	return defaultAccessor.pathExists(path, node)
+}
+
+// Value returns the value at the supplied path or nil if the path does not exist of points to a null value.
+//
+// Elements of arrays can be addressed with either a numeric path element (servers.0.host) or a bracketed index
+// (servers[0].host). Negative indexes count back from the end of the array, so servers[-1] is the last element.
+// A bracketed slice (servers[1:3]) returns a range of elements as an array and must be the last element of the path.
+//
+// Keys that contain the separator or other special characters can either be enclosed in double quotes
+// (hosts."example.com".timeout) or have the special characters escaped with a backslash (hosts.example\.com.timeout).
+// BuildPath can be used to create paths with any necessary quoting.
+func Value(path string, node ConfigNode) interface{} {
+	return defaultAccessor.value(path, node)
+}
+
+// Lookup returns the value at the supplied path. If there is no value at the path, or the value is null, a PathError is
+// returned that identifies the element of the path that could not be matched and the kind of value found in its place.
+// An error is also returned if the path cannot be parsed.
+func Lookup(path string, node ConfigNode) (interface{}, error) {
+	return defaultAccessor.lookup(path, node)
 }
 
 // ObjectVal returns a map representing an object or nil if the path does not exist or points to a null value. An error
 // is returned if the value cannot be interpreted as an object (a key in the configuration that has child keys rather than
-// a value.
+// a value. Objects with non-string keys (as produced by some YAML parsers) are returned as a copy with string keys.
 // If errIfMissing is set to true, an error will be return if the supplied path does not exist otherwise a nil
 // array without and error will be returned.
 func ObjectVal(path string, node ConfigNode, errIfMissing bool) (ConfigNode, error) {
+	return defaultAccessor.objectVal(path, node, errIfMissing)
+}
+
+// StringVal returns the string value of the string at the supplied path. Does not convert other types to
+// a string, so will return an error if the value is not already a string.
+func StringVal(path string, node ConfigNode) (string, error) {
+	return defaultAccessor.stringVal(path, node)
+}
+
+// IntVal returns the int value of the number at the supplied path. JSON numbers are internally represented by Go as a
+// float64, so an InvalidValueError is returned if the number is not a whole number and an OutOfRangeError if it is
+// outside the range of an int. A TypeMismatchError is returned if the value is not a number.
+func IntVal(path string, node ConfigNode) (int, error) {
+	return defaultAccessor.intVal(path, node)
+}
+
+// Float64Val returns the float64 value of the  number at the supplied path. An error will be returned if the value is not a number.
+func Float64Val(path string, node ConfigNode) (float64, error) {
+	return defaultAccessor.float64Val(path, node)
+}
+
+// Array returns the value of an array of objects at the supplied path. Caution should be used when calling this method
+// as behaviour is undefined for arrays of types other than []interface.
+//
+// If errIfMissing is set to true, an error will be return if the supplied path does not exist otherwise a nil
+// array without an error will be returned.
+func Array(path string, node ConfigNode, errIfMissing bool) ([]interface{}, error) {
+	return defaultAccessor.array(path, node, errIfMissing)
+}
+
+// StringArray returns an array of strings from the value at the supplied path.
+//
+// An error is returned if there is no value at the supplied path or if the value cannot be interpreted as []string
+func StringArray(path string, node ConfigNode) ([]string, error) {
+	return defaultAccessor.stringArray(path, node)
+}
+
+// IntArray returns an array of int from the value at the supplied path.
+//
+// An error is returned if there is no value at the supplied path or if the value cannot be interpreted as []int
+func IntArray(path string, node ConfigNode) ([]int, error) {
+	return defaultAccessor.intArray(path, node)
+}
+
+// Float64Array returns an array of float64s from the value at the supplied path.
+//
+// An error is returned if there is no value at the supplied path or if the value cannot be interpreted as []float64
+func Float64Array(path string, node ConfigNode) ([]float64, error) {
+	return defaultAccessor.float64Array(path, node)
+}
+
+// BoolVal returns the bool value of the bool at the supplied path. An error will be returned if the value is not a JSON bool.
+// Note this method only supports the JSON definition of bool (true, false) not the Go definition (true, false, 1, 0 etc) or
+// extended YAML definitions.
+func BoolVal(path string, node ConfigNode) (bool, error) {
+	return defaultAccessor.boolVal(path, node)
+}
+
+// Decode converts the value at the supplied path to the type of target, which must be a non-nil pointer. See Get
+// for the types that are supported.
+func Decode(path string, node ConfigNode, target interface{}, o ...Opts) error {
+	return defaultAccessor.decode(path, node, target, options(o))
+}
+
+func (a accessor) pathExists(path string, node ConfigNode) bool {
+	value := a.value(path, node)
+
+	return value != nil
+}
+
+func (a accessor) value(path string, node ConfigNode) interface{} {
+
+	v, _ := a.lookup(path, node)
+
+	return v
+}
+
+func (a accessor) lookup(path string, node ConfigNode) (interface{}, error) {
+
+	if node == nil {
+		return nil, ErrNilConfig
+	}
+
+	segments, err := parsePath(path, a.separator())
+
+	if err != nil {
+		return nil, err
+	}
+
+	v, stop := follow(segments, node)
+
+	if stop != nil {
+		return nil, newPathError(path, segments, stop, a.separator())
+	}
+
+	return v, nil
+}
+
+// optionalLookup behaves like lookup, but does not return an error if the value at the path is simply missing or null
+func (a accessor) optionalLookup(path string, node ConfigNode) (interface{}, error) {
+
+	v, err := a.lookup(path, node)
+
+	if pe, found := err.(PathError); found && pe.Missing() {
+		return nil, nil
+	}
+
+	return v, err
+}
+
+func (a accessor) decode(path string, node ConfigNode, target interface{}, opts Opts) error {
+
+	tv := reflect.ValueOf(target)
+
+	if tv.Kind() != reflect.Pointer || tv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, not %T", target)
+	}
+
+	t := tv.Elem().Type()
+
+	v, err := a.lookup(path, node)
+
+	var mpe MissingPathError
+
+	if opts.OnMissing != nil && errors.As(err, &mpe) {
+
+		def := reflect.ValueOf(opts.OnMissing)
+
+		if !def.Type().AssignableTo(t) {
+			return TypeMismatchError{Path: path, Expected: t.String(), Actual: def.Type().String()}
+		}
+
+		tv.Elem().Set(def)
+
+		return nil
+
+	} else if err != nil {
+		return err
+	}
+
+	cv, err := a.conversion(node, opts).convert(path, v, t)
+
+	if err != nil {
+		return err
+	}
+
+	tv.Elem().Set(cv)
+
+	return nil
+}
+
+// decodeAs converts the value at the supplied path to a T
+func decodeAs[T any](a accessor, path string, node ConfigNode, opts Opts) (T, error) {
+
+	var t T
+
+	err := a.decode(path, node, &t, opts)
+
+	return t, err
+}
+
+func (a accessor) objectVal(path string, node ConfigNode, errIfMissing bool) (ConfigNode, error) {
 
 	if node == nil {
-		return nil, fmt.Errorf("supplied ConfigNode is nil")
+		return nil, ErrNilConfig
 	}
 
-	if errIfMissing && !PathExists(path, node) {
-		return nil, errors.New("No such path " + path)
+	var value interface{}
+	var err error
+
+	if errIfMissing {
+		value, err = a.lookup(path, node)
+	} else {
+		value, err = a.optionalLookup(path, node)
 	}
 
-	value := Value(path, node)
+	if err != nil {
+		return nil, err
+	}
 
 	if value == nil {
 		return nil, nil
 	} else if v, found := value.(ConfigNode); found {
 		return v, nil
+	} else if v, found := value.(map[interface{}]interface{}); found {
+		return stringKeys(v), nil
 	}
 
-	return nil, fmt.Errorf("unable to convert the value at %s to a ConfigNode", path)
+	return nil, TypeMismatchError{Path: path, Expected: "object", Actual: kindOf(value)}
 }
 
-// StringVal returns the string value of the string at the supplied path. Does not convert other types to
-// a string, so will return an error if the value is not already a string.
-func StringVal(path string, node ConfigNode) (string, error) {
+func (a accessor) stringVal(path string, node ConfigNode) (string, error) {
+
+	if a.coerce || a.interpolate || len(a.resolvers) > 0 {
+		return decodeAs[string](a, path, node, Opts{})
+	}
 
 	if node == nil {
-		return "", fmt.Errorf("supplied ConfigNode is nil")
+		return "", ErrNilConfig
 	}
 
-	v := Value(path, node)
+	v, err := a.lookup(path, node)
 
-	if v == nil {
-		return "", errors.New("No string value found at " + path)
+	if err != nil {
+		return "", err
 	}
 
 	s, found := v.(string)
@@ -71,68 +326,75 @@ func StringVal(path string, node ConfigNode) (string, error) {
 		return s, nil
 	}
 
-	return "", fmt.Errorf("Value at %s is %q and cannot be converted to a string", path, v)
+	return "", TypeMismatchError{Path: path, Expected: "string", Actual: kindOf(v)}
 
 }
 
-// IntVal returns the int value of the  number at the supplied path. JSON numbers
-// are internally represented by Go as a float64, so no error will be returned, but data might be lost
-// if the number does not actually represent an int. An error will be returned if the value is not a number
-// or cannot be converted to an int.
-func IntVal(path string, node ConfigNode) (int, error) {
+func (a accessor) intVal(path string, node ConfigNode) (int, error) {
+
+	if a.coerce {
+		return decodeAs[int](a, path, node, Opts{})
+	}
 
 	if node == nil {
-		return 0, fmt.Errorf("supplied ConfigNode is nil")
+		return 0, ErrNilConfig
 	}
 
-	v := Value(path, node)
+	v, err := a.lookup(path, node)
 
-	if v == nil {
-		return 0, errors.New("No such path " + path)
-	} else if f, found := v.(float64); found {
-		return int(f), nil
-	} else if i, found := v.(int); found {
-		return i, nil
+	if err != nil {
+		return 0, err
 	}
 
-	return 0, fmt.Errorf("Value at %s is %q and cannot be converted to an int", path, v)
+	i, err := convertInteger(path, v, reflect.TypeOf(0))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int(i.Int()), nil
 
 }
 
-// Float64Val returns the float64 value of the  number at the supplied path. An error will be returned if the value is not a number.
-func Float64Val(path string, node ConfigNode) (float64, error) {
+func (a accessor) float64Val(path string, node ConfigNode) (float64, error) {
+
+	if a.coerce {
+		return decodeAs[float64](a, path, node, Opts{})
+	}
 
 	if node == nil {
-		return 0, fmt.Errorf("supplied ConfigNode is nil")
+		return 0, ErrNilConfig
 	}
 
-	v := Value(path, node)
+	v, err := a.lookup(path, node)
 
-	if v == nil {
-		return 0, errors.New("No such path " + path)
-	} else if f, found := v.(float64); found {
+	if err != nil {
+		return 0, err
+	} else if f, found := numberValue(v); found {
 		return f, nil
 	}
 
-	return 0, fmt.Errorf("value at %s is %q and cannot be converted to a float64", path, v)
+	return 0, TypeMismatchError{Path: path, Expected: "float64", Actual: kindOf(v)}
 }
 
-// Array returns the value of an array of objects at the supplied path. Caution should be used when calling this method
-// as behaviour is undefined for arrays of types other than []interface.
-//
-// If errIfMissing is set to true, an error will be return if the supplied path does not exist otherwise a nil
-// array without an error will be returned.
-func Array(path string, node ConfigNode, errIfMissing bool) ([]interface{}, error) {
+func (a accessor) array(path string, node ConfigNode, errIfMissing bool) ([]interface{}, error) {
 
 	if node == nil {
-		return nil, fmt.Errorf("supplied ConfigNode is nil")
+		return nil, ErrNilConfig
 	}
 
-	if errIfMissing && !PathExists(path, node) {
-		return nil, errors.New("No such path " + path)
+	var value interface{}
+	var err error
+
+	if errIfMissing {
+		value, err = a.lookup(path, node)
+	} else {
+		value, err = a.optionalLookup(path, node)
 	}
 
-	value := Value(path, node)
+	if err != nil {
+		return nil, err
+	}
 
 	if value == nil {
 		return nil, nil
@@ -140,124 +402,141 @@ func Array(path string, node ConfigNode, errIfMissing bool) ([]interface{}, erro
 		return v, nil
 	}
 
-	return nil, fmt.Errorf("unable to convert the value at %s to an array", path)
+	return nil, TypeMismatchError{Path: path, Expected: "array", Actual: kindOf(value)}
 
 }
 
-// StringArray returns an array of strings from the value at the supplied path.
-//
-// An error is returned if there is no value at the supplied path or if the value cannot be interpreted as []string
-func StringArray(path string, node ConfigNode) ([]string, error) {
+func (a accessor) stringArray(path string, node ConfigNode) ([]string, error) {
+
+	if a.coerce || a.interpolate || len(a.resolvers) > 0 {
+		return decodeAs[[]string](a, path, node, Opts{})
+	}
 
-	ival, err := Array(path, node, true)
+	ival, err := a.array(path, node, true)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return toStringArray(path, ival)
+}
+
+// toStringArray converts the elements of an array found at (or by) the supplied path to strings
+func toStringArray(path string, ival []interface{}) ([]string, error) {
+
 	sval := make([]string, len(ival))
 
 	okay := true
 
 	for i, v := range ival {
 		if sval[i], okay = v.(string); !okay {
-			return nil, fmt.Errorf("value at %s[%d] is %v and cannot be converted to a string", path, i, v)
+			return nil, TypeMismatchError{Path: elementPath(path, i), Expected: "string", Actual: kindOf(v)}
 		}
 	}
 
 	return sval, nil
 }
 
-// IntArray returns an array of int from the value at the supplied path.
-//
-// An error is returned if there is no value at the supplied path or if the value cannot be interpreted as []int
-func IntArray(path string, node ConfigNode) ([]int, error) {
+func (a accessor) intArray(path string, node ConfigNode) ([]int, error) {
 
-	ival, err := Array(path, node, true)
+	if a.coerce {
+		return decodeAs[[]int](a, path, node, Opts{})
+	}
+
+	ival, err := a.array(path, node, true)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return toIntArray(path, ival)
+}
+
+// toIntArray converts the elements of an array found at (or by) the supplied path to ints
+func toIntArray(path string, ival []interface{}) ([]int, error) {
+
 	typedVal := make([]int, len(ival))
 
 	for i, v := range ival {
-		switch t := v.(type) {
-		case int:
-			typedVal[i] = t
-		case float64:
-			typedVal[i] = int(t)
-		default:
-			return nil, fmt.Errorf("value at %s[%d] is %v of type %T and cannot be converted to an int", path, i, v, t)
+
+		iv, err := convertInteger(elementPath(path, i), v, reflect.TypeOf(0))
+
+		if err != nil {
+			return nil, err
 		}
+
+		typedVal[i] = int(iv.Int())
 	}
 
 	return typedVal, nil
 }
 
-// Float64Array returns an array of float64s from the value at the supplied path.
-//
-// An error is returned if there is no value at the supplied path or if the value cannot be interpreted as []float64
-func Float64Array(path string, node ConfigNode) ([]float64, error) {
+func (a accessor) float64Array(path string, node ConfigNode) ([]float64, error) {
+
+	if a.coerce {
+		return decodeAs[[]float64](a, path, node, Opts{})
+	}
 
-	ival, err := Array(path, node, true)
+	ival, err := a.array(path, node, true)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return toFloat64Array(path, ival)
+}
+
+// toFloat64Array converts the elements of an array found at (or by) the supplied path to float64s
+func toFloat64Array(path string, ival []interface{}) ([]float64, error) {
+
 	typedVal := make([]float64, len(ival))
 
 	for i, v := range ival {
-		switch t := v.(type) {
-		case int:
-			typedVal[i] = float64(t)
-		case float64:
-			typedVal[i] = t
-		default:
-			return nil, fmt.Errorf("value at %s[%d] is %v of type %T and cannot be converted to a float64", path, i, v, t)
+
+		f, found := numberValue(v)
+
+		if !found {
+			return nil, TypeMismatchError{Path: elementPath(path, i), Expected: "float64", Actual: kindOf(v)}
 		}
+
+		typedVal[i] = f
 	}
 
 	return typedVal, nil
 }
 
-// BoolVal returns the bool value of the bool at the supplied path. An error will be returned if the value is not a JSON bool.
-// Note this method only supports the JSON definition of bool (true, false) not the Go definition (true, false, 1, 0 etc) or
-// extended YAML definitions.
-func BoolVal(path string, node ConfigNode) (bool, error) {
+func (a accessor) boolVal(path string, node ConfigNode) (bool, error) {
+
+	if a.coerce {
+		return decodeAs[bool](a, path, node, Opts{})
+	}
 
 	if node == nil {
-		return false, fmt.Errorf("supplied ConfigNode is nil")
+		return false, ErrNilConfig
 	}
 
-	v := Value(path, node)
+	v, err := a.lookup(path, node)
 
-	if v == nil {
-		return false, errors.New("No such path " + path)
+	if err != nil {
+		return false, err
 	}
 
 	if b, found := v.(bool); found {
 		return b, nil
 	}
 
-	return false, fmt.Errorf("Value at %s is %q and cannot be converted to a bool", path, v)
+	return false, TypeMismatchError{Path: path, Expected: "bool", Actual: kindOf(v)}
 
 }
 
-func configVal(path []string, jsonMap ConfigNode) interface{} {
+// stringKeys creates a ConfigNode from an object with non-string keys
+func stringKeys(m map[interface{}]interface{}) ConfigNode {
 
-	var result interface{}
-	result = jsonMap[path[0]]
-
-	if result == nil {
-		return nil
-	}
+	node := make(ConfigNode, len(m))
 
-	if len(path) == 1 {
-		return result
+	for k, v := range m {
+		node[fmt.Sprint(k)] = v
 	}
 
-	remainPath := path[1:]
-	return configVal(remainPath, result.(ConfigNode))
+	return node
 }
@@ -1,6 +1,7 @@
 package config_access_test
 
 import (
+	"errors"
 	ca "github.com/graniticio/config-access"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -104,3 +105,89 @@ func TestFloat64Array(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestTraversalThroughScalars(t *testing.T) {
+	jsonConf := loadJsonTestFile(t, "simple.json")
+	yamlConf := loadYamlTestFile(t, "simple.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		assert.False(t, ca.PathExists("simpleOne.String.foo", node))
+		assert.Nil(t, ca.Value("simpleOne.Bool.foo.bar", node))
+
+		_, err := ca.StringVal("simpleOne.String.foo", node)
+
+		var pe ca.PathError
+		assert.True(t, errors.As(err, &pe))
+		assert.EqualValues(t, "simpleOne.String.foo", pe.Path)
+		assert.EqualValues(t, "foo", pe.Segment)
+		assert.EqualValues(t, "simpleOne.String", pe.Parent)
+		assert.EqualValues(t, "string", pe.Found)
+		assert.False(t, pe.Missing())
+
+		_, err = ca.IntVal("simpleOne.StringArray.first", node)
+		assert.True(t, errors.As(err, &pe))
+		assert.EqualValues(t, "array", pe.Found)
+		assert.False(t, pe.Missing())
+
+		_, err = ca.IntVal("simpleOne.StringArray[7]", node)
+		assert.True(t, errors.As(err, &pe))
+		assert.EqualValues(t, "[7]", pe.Segment)
+		assert.EqualValues(t, "array", pe.Found)
+		assert.True(t, pe.Missing())
+
+		_, err = ca.BoolVal("simpleOne.Missing.x", node)
+		assert.True(t, errors.As(err, &pe))
+		assert.EqualValues(t, "Missing", pe.Segment)
+		assert.EqualValues(t, "object", pe.Found)
+		assert.True(t, pe.Missing())
+
+		// Missing paths are not errors if errIfMissing is false, but paths through scalars are
+		a, err := ca.Array("simpleOne.Missing.x", node, false)
+		assert.Nil(t, a)
+		assert.NoError(t, err)
+
+		a, err = ca.Array("simpleOne.Float.x", node, false)
+		assert.Nil(t, a)
+		assert.Error(t, err)
+
+		o, err := ca.ObjectVal("simpleOne.Int.x", node, false)
+		assert.Nil(t, o)
+		assert.Error(t, err)
+
+		_, err = ca.Lookup("simpleOne.Int.x", node)
+		assert.Error(t, err)
+
+		v, err := ca.Lookup("simpleOne.Int", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 32, v)
+	}
+}
+
+func TestTraversalOfNonStringKeys(t *testing.T) {
+
+	node := ca.ConfigNode{
+		"ports": map[interface{}]interface{}{
+			80:   map[interface{}]interface{}{"name": "http"},
+			true: "yes",
+		},
+	}
+
+	s, err := ca.StringVal("ports.80.name", node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "http", s)
+
+	s, err = ca.StringVal("ports.true", node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "yes", s)
+
+	assert.False(t, ca.PathExists("ports.443", node))
+
+	o, err := ca.ObjectVal("ports.80", node, true)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "http", o["name"])
+
+	pvs, err := ca.Select("ports.*", node)
+	assert.NoError(t, err)
+	assert.Len(t, pvs, 2)
+}
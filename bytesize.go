@@ -0,0 +1,235 @@
+package config_access
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// ByteSize is a number of bytes. In configuration, sizes can be written as a whole number of bytes or as a string with
+// an SI (decimal) or IEC (binary) unit, e.g. "512k", "1.5GB" or "64MiB". See ParseByteSize for details.
+type ByteSize uint64
+
+// Commonly used sizes
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB          = 1000 * KB
+	GB          = 1000 * MB
+	TB          = 1000 * GB
+	PB          = 1000 * TB
+	EB          = 1000 * PB
+
+	KiB ByteSize = 1024 * Byte
+	MiB          = 1024 * KiB
+	GiB          = 1024 * MiB
+	TiB          = 1024 * GiB
+	PiB          = 1024 * TiB
+	EiB          = 1024 * PiB
+)
+
+var byteSizeUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	{"EB", EB}, {"PB", PB}, {"TB", TB}, {"GB", GB}, {"MB", MB}, {"kB", KB},
+}
+
+// String returns the size using the largest unit that represents it exactly (preferring IEC units), e.g. 64MiB,
+// 1500kB or 17B.
+func (b ByteSize) String() string {
+
+	for _, u := range byteSizeUnits {
+		if b != 0 && b%u.size == 0 {
+			return fmt.Sprintf("%d%s", b/u.size, u.name)
+		}
+	}
+
+	return fmt.Sprintf("%dB", uint64(b))
+}
+
+var siMultipliers = map[string]int64{
+	"k": 1e3, "K": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+}
+
+var siFractions = map[string]int64{
+	"m": 1e3, "u": 1e6, "µ": 1e6, "n": 1e9,
+}
+
+var iecMultipliers = map[string]int64{
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// byteSizeSuffixes maps the units accepted by ParseByteSize, in lower case, to their multipliers
+var byteSizeSuffixes = func() map[string]int64 {
+
+	suffixes := map[string]int64{"": 1, "b": 1}
+
+	for prefix, m := range siMultipliers {
+		suffixes[strings.ToLower(prefix)] = m
+		suffixes[strings.ToLower(prefix)+"b"] = m
+	}
+
+	for prefix, m := range iecMultipliers {
+		suffixes[strings.ToLower(prefix)] = m
+		suffixes[strings.ToLower(prefix)+"b"] = m
+	}
+
+	return suffixes
+}()
+
+// ParseQuantity parses a number with an optional SI or IEC suffix, e.g. 500m (0.5), 2.5k (2500), 3M (3,000,000) or
+// 1Gi (1,073,741,824). The SI suffixes are n, u (or µ), m, k (or K), M, G, T, P and E. The IEC suffixes are Ki, Mi, Gi,
+// Ti, Pi and Ei. Suffixes are case-sensitive, so m is milli and M is mega.
+func ParseQuantity(s string) (float64, error) {
+
+	number, suffix, err := splitQuantity(s)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if suffix != "" {
+
+		if m, found := siMultipliers[suffix]; found {
+			number.Mul(number, new(big.Float).SetInt64(m))
+		} else if m, found := iecMultipliers[suffix]; found {
+			number.Mul(number, new(big.Float).SetInt64(m))
+		} else if d, found := siFractions[suffix]; found {
+			number.Quo(number, new(big.Float).SetInt64(d))
+		} else {
+			return 0, fmt.Errorf("unknown unit %q in quantity %q", suffix, s)
+		}
+	}
+
+	f, _ := number.Float64()
+
+	if math.IsInf(f, 0) {
+		return 0, fmt.Errorf("quantity %q is too large", s)
+	}
+
+	return f, nil
+}
+
+// ParseByteSize parses a size in bytes. The size may be a whole number of bytes or a number followed by a unit. SI units
+// (k, M, G, T, P, E) are powers of 1000 and IEC units (Ki, Mi, Gi, Ti, Pi, Ei) are powers of 1024. Units may be followed
+// by B and are case-insensitive, so 512k, 512kB and 512KB are all 512,000 bytes and 64Mi, 64MiB and 64mib are all
+// 67,108,864 bytes. Fractional numbers are allowed as long as the result is a whole number of bytes (e.g. 1.5GB).
+func ParseByteSize(s string) (ByteSize, error) {
+
+	number, suffix, err := splitQuantity(s)
+
+	if err != nil {
+		return 0, err
+	}
+
+	multiplier, found := byteSizeSuffixes[strings.ToLower(suffix)]
+
+	if !found {
+		return 0, fmt.Errorf("unknown unit %q in size %q", suffix, s)
+	}
+
+	number.Mul(number, new(big.Float).SetInt64(multiplier))
+
+	if !number.IsInt() {
+		return 0, fmt.Errorf("size %q is not a whole number of bytes", s)
+	}
+
+	if number.Sign() < 0 {
+		return 0, fmt.Errorf("size %q is negative", s)
+	}
+
+	i, accuracy := number.Uint64()
+
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+
+	return ByteSize(i), nil
+}
+
+// splitQuantity separates a quantity into its number and its (possibly empty) unit, which may be separated by spaces
+func splitQuantity(s string) (*big.Float, string, error) {
+
+	s = strings.TrimSpace(s)
+
+	end := 0
+
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || (end == 0 && (s[end] == '-' || s[end] == '+'))) {
+		end++
+	}
+
+	number, _, err := big.ParseFloat(s[:end], 10, 128, big.ToNearestEven)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("%q does not start with a number", s)
+	}
+
+	return number, strings.TrimSpace(s[end:]), nil
+}
+
+var byteSizeType = reflect.TypeOf(ByteSize(0))
+
+func convertByteSize(c conversion, path string, v interface{}) (interface{}, error) {
+
+	if s, found := v.(string); found {
+
+		b, err := ParseByteSize(s)
+
+		if err != nil {
+			return nil, InvalidValueError{Path: path, Expected: "size", Err: err}
+		}
+
+		return b, nil
+	}
+
+	u, err := convertInteger(path, v, byteSizeType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Interface(), nil
+}
+
+// ByteSizeVal returns the size in bytes represented by the value at the supplied path. See ParseByteSize for the
+// formats that are supported.
+func ByteSizeVal(path string, node ConfigNode, o ...Opts) (ByteSize, error) {
+	return decodeAs[ByteSize](defaultAccessor, path, node, options(o))
+}
+
+// QuantityVal returns the number at the supplied path or, if the value is a string, the result of parsing it with
+// ParseQuantity.
+func QuantityVal(path string, node ConfigNode, o ...Opts) (float64, error) {
+	return defaultAccessor.quantityVal(path, node, options(o))
+}
+
+func (a accessor) quantityVal(path string, node ConfigNode, opts Opts) (float64, error) {
+
+	var v interface{}
+
+	if err := a.decode(path, node, &v, opts); err != nil {
+		return 0, err
+	}
+
+	if s, found := v.(string); found {
+
+		f, err := ParseQuantity(s)
+
+		if err != nil {
+			return 0, InvalidValueError{Path: path, Expected: "quantity", Err: err}
+		}
+
+		return f, nil
+	}
+
+	if f, found := numberValue(v); found {
+		return f, nil
+	}
+
+	return 0, TypeMismatchError{Path: path, Expected: "quantity", Actual: kindOf(v)}
+}
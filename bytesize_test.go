@@ -0,0 +1,198 @@
+package config_access_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+type Buffers struct {
+	Read   ca.ByteSize
+	Write  ca.ByteSize
+	Upload *ca.ByteSize
+	Bytes  ca.ByteSize
+}
+
+func TestParseByteSize(t *testing.T) {
+
+	valid := map[string]ca.ByteSize{
+		"0":        0,
+		"17":       17,
+		"17B":      17,
+		"17b":      17,
+		"512k":     512000,
+		"512kB":    512000,
+		"512KB":    512000,
+		"1.5GB":    1500000000,
+		"64Mi":     64 * ca.MiB,
+		"64MiB":    64 * ca.MiB,
+		"64mib":    64 * ca.MiB,
+		"2 TiB":    2 * ca.TiB,
+		" 1.5 Ki ": 1536,
+		"15EiB":    15 * ca.EiB,
+	}
+
+	for s, expected := range valid {
+		b, err := ca.ParseByteSize(s)
+		assert.Nil(t, err, s)
+		assert.EqualValues(t, expected, b, s)
+	}
+
+	for _, s := range []string{"", "MB", "1.1B", "-5MB", "12 furlongs", "16EiB", "1.0.0k", "1Bb", "1bB", "1kbB", "1KiBb", "1iB"} {
+		_, err := ca.ParseByteSize(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+
+	valid := map[string]float64{
+		"12":    12,
+		"-3.5":  -3.5,
+		"500m":  0.5,
+		"250u":  0.00025,
+		"2.5k":  2500,
+		"3M":    3000000,
+		"1Gi":   1 << 30,
+		"1.5 G": 1500000000,
+	}
+
+	for s, expected := range valid {
+		q, err := ca.ParseQuantity(s)
+		assert.Nil(t, err, s)
+		assert.InDelta(t, expected, q, 1e-9, s)
+	}
+
+	for _, s := range []string{"", "k", "1MB", "3 furlongs"} {
+		_, err := ca.ParseQuantity(s)
+		assert.NotNil(t, err, s)
+	}
+
+	// Quantities too large for a float64 are rejected rather than becoming infinite
+	huge := strings.Repeat("9", 400) + "E"
+
+	_, err := ca.ParseQuantity(huge)
+	assert.Contains(t, err.Error(), "too large")
+
+	_, err = ca.QuantityVal("q", ca.ConfigNode{"q": "-" + huge})
+
+	var ive ca.InvalidValueError
+	assert.True(t, errors.As(err, &ive))
+	assert.EqualValues(t, "q", ive.Path)
+}
+
+func TestByteSizeString(t *testing.T) {
+
+	assert.EqualValues(t, "0B", ca.ByteSize(0).String())
+	assert.EqualValues(t, "17B", ca.ByteSize(17).String())
+	assert.EqualValues(t, "64MiB", (64 * ca.MiB).String())
+	assert.EqualValues(t, "1500MB", ca.ByteSize(1500000000).String())
+	assert.EqualValues(t, "1536B", ca.ByteSize(1536).String())
+	assert.EqualValues(t, "3KiB", ca.ByteSize(3072).String())
+}
+
+func TestByteSizeAndQuantityVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "sizes.json")
+	yamlConf := loadYamlTestFile(t, "sizes.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		b, err := cs.ByteSizeVal("buffers.read")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 64*ca.MiB, b)
+
+		b, err = cs.ByteSizeVal("buffers.bytes")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 4096, b)
+
+		var ive ca.InvalidValueError
+
+		for _, path := range []string{"buffers.half", "buffers.unknown"} {
+			_, err = cs.ByteSizeVal(path)
+			assert.True(t, errors.As(err, &ive), path)
+			assert.EqualValues(t, path, ive.Path)
+		}
+
+		var ore ca.OutOfRangeError
+
+		_, err = cs.ByteSizeVal("buffers.negative")
+		assert.True(t, errors.As(err, &ore))
+
+		q, err := cs.QuantityVal("quantities.cpu")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0.5, q)
+
+		q, err = cs.QuantityVal("quantities.memory")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1<<31, q)
+
+		q, err = cs.QuantityVal("quantities.rate")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1200, q)
+
+		_, err = cs.QuantityVal("quantities.bad")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "quantities.bad", ive.Path)
+
+		var mpe ca.MissingPathError
+
+		_, err = ca.QuantityVal("quantities.missing", node)
+		assert.True(t, errors.As(err, &mpe))
+
+		sizes, err := ca.Get[map[string]ca.ByteSize](cs, "buffers", ca.Opts{})
+		assert.NotNil(t, err)
+		assert.Nil(t, sizes)
+
+		var bf Buffers
+
+		assert.Nil(t, ca.SetField("Read", "buffers.read", &bf, node))
+		assert.Nil(t, ca.SetField("Upload", "buffers.upload", &bf, node))
+		assert.EqualValues(t, 64*ca.MiB, bf.Read)
+		assert.EqualValues(t, 1500*ca.MB, *bf.Upload)
+
+		bf = Buffers{}
+
+		assert.Nil(t, ca.Populate("buffers", &bf, node))
+		assert.EqualValues(t, Buffers{Read: 64 * ca.MiB, Write: 512 * ca.KB, Upload: bf.Upload, Bytes: 4096}, bf)
+		assert.EqualValues(t, 1500*ca.MB, *bf.Upload)
+	}
+}
+
+func TestByteSizeAndQuantityValWithOpts(t *testing.T) {
+
+	node := ca.ConfigNode{"buffer": "${size}", "size": "64MiB", "cpu": "${cores}m", "cores": 500.0}
+
+	b, err := ca.ByteSizeVal("buffer", node, ca.Opts{Interpolate: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 64*ca.MiB, b)
+
+	q, err := ca.QuantityVal("cpu", node, ca.Opts{Interpolate: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0.5, q)
+
+	b, err = ca.ByteSizeVal("missing", node, ca.Opts{OnMissing: ca.KiB})
+	assert.Nil(t, err)
+	assert.EqualValues(t, ca.KiB, b)
+}
+
+func TestQuietByteSizeAndQuantity(t *testing.T) {
+
+	var failed []string
+
+	qs := ca.NewDeferredErrorQuietSelector(ca.NewDefaultSelector(loadJsonTestFile(t, "sizes.json"), true, true), func(path string, err error) {
+		failed = append(failed, path)
+	})
+
+	assert.EqualValues(t, 512000, qs.ByteSizeVal("buffers.write"))
+	assert.EqualValues(t, 0, qs.ByteSizeVal("buffers.unknown"))
+	assert.EqualValues(t, 0.5, qs.QuantityVal("quantities.cpu"))
+	assert.EqualValues(t, 0, qs.QuantityVal("quantities.bad"))
+
+	assert.EqualValues(t, []string{"buffers.unknown", "quantities.bad"}, failed)
+}
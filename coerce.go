@@ -0,0 +1,82 @@
+package config_access
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// yamlBooleans are the strings that YAML 1.1 interprets as booleans (matched case-insensitively), plus 1 and 0
+var yamlBooleans = map[string]bool{
+	"true": true, "yes": true, "y": true, "on": true, "1": true,
+	"false": false, "no": false, "n": false, "off": false, "0": false,
+}
+
+// coerce converts a string, number or bool to the representation required to populate a value of the supplied type,
+// following the rules described in SelectorOpts.Coerce. Values of other kinds, or that are already of the required
+// kind, are returned unchanged.
+func coerce(path string, v interface{}, t reflect.Type) (interface{}, error) {
+
+	switch t.Kind() {
+	case reflect.String:
+		if b, found := v.(bool); found {
+			return strconv.FormatBool(b), nil
+		}
+
+		if i, err := integerValue(v); i != nil && err == nil {
+			return i.String(), nil
+		}
+
+		if f, found := numberValue(v); found {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+	case reflect.Bool:
+		if s, found := v.(string); found {
+
+			if b, found := yamlBooleans[strings.ToLower(strings.TrimSpace(s))]; found {
+				return b, nil
+			}
+
+			return nil, InvalidValueError{Path: path, Expected: t.String(), Err: fmt.Errorf("%q is not a recognised boolean", s)}
+		}
+
+		if f, found := numberValue(v); found {
+
+			if f == 0 || f == 1 {
+				return f == 1, nil
+			}
+
+			return nil, InvalidValueError{Path: path, Expected: t.String(), Err: fmt.Errorf("only 0 and 1 can be converted to a bool, not %v", f)}
+		}
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8,
+		reflect.Float64, reflect.Float32:
+
+		switch n := v.(type) {
+		case string:
+			trimmed := strings.TrimSpace(n)
+
+			f, err := strconv.ParseFloat(trimmed, 64)
+
+			if err != nil {
+				return nil, InvalidValueError{Path: path, Expected: t.String(), Err: fmt.Errorf("%q is not a number", n)}
+			} else if math.IsInf(f, 0) || math.IsNaN(f) {
+				// ParseFloat accepts NaN, Inf and Infinity, which are not numbers that can be written in configuration
+				return nil, InvalidValueError{Path: path, Expected: t.String(), Err: fmt.Errorf("%q is not a finite number", n)}
+			}
+
+			return json.Number(trimmed), nil
+		case bool:
+			if n {
+				return float64(1), nil
+			}
+
+			return float64(0), nil
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,171 @@
+package config_access_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"server.port":     "8080",
+		"server.ratio":    " 0.75 ",
+		"server.debug":    "Yes",
+		"server.tls":      "off",
+		"server.verbose":  "1",
+		"server.timeout":  "2500",
+		"server.name":     "api",
+		"server.ports":    []interface{}{"80", 443.0},
+		"server.flags":    []interface{}{"on", "N", true, 0.0},
+		"server.replicas": 3.0,
+		"server.enabled":  true,
+		"server.weight":   32.5,
+		"server.big":      float64(1 << 40),
+		"server.fraction": "1.5",
+		"server.level":    2.0,
+		"server.maybe":    "perhaps",
+	}
+}
+
+func TestCoercionIsOffByDefault(t *testing.T) {
+
+	cs := ca.SelectorFromPathValues(stringConfig())
+
+	var tme ca.TypeMismatchError
+
+	_, err := cs.IntVal("server.port")
+	assert.True(t, errors.As(err, &tme))
+
+	_, err = cs.BoolVal("server.debug")
+	assert.True(t, errors.As(err, &tme))
+
+	_, err = cs.StringVal("server.replicas")
+	assert.True(t, errors.As(err, &tme))
+}
+
+func TestSelectorCoercion(t *testing.T) {
+
+	cs := ca.SelectorFromPathValues(stringConfig(), ca.SelectorOpts{Coerce: true})
+
+	i, err := cs.IntVal("server.port")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8080, i)
+
+	u16, err := cs.Uint16Val("server.port")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8080, u16)
+
+	f, err := cs.Float64Val("server.ratio")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0.75, f)
+
+	for path, expected := range map[string]bool{"server.debug": true, "server.tls": false, "server.verbose": true} {
+		b, err := cs.BoolVal(path)
+		assert.Nil(t, err, path)
+		assert.EqualValues(t, expected, b, path)
+	}
+
+	s, err := cs.StringVal("server.replicas")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "3", s)
+
+	s, err = cs.StringVal("server.weight")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "32.5", s)
+
+	s, err = cs.StringVal("server.big")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "1099511627776", s)
+
+	s, err = cs.StringVal("server.enabled")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "true", s)
+
+	ports, err := cs.IntArray("server.ports")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []int{80, 443}, ports)
+
+	names, err := cs.StringArray("server.ports")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"80", "443"}, names)
+
+	flags, err := ca.Get[[]bool](cs, "server.flags")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []bool{true, false, true, false}, flags)
+
+	d, err := cs.DurationVal("server.timeout")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2500*time.Millisecond, d)
+
+	var ive ca.InvalidValueError
+
+	_, err = cs.IntVal("server.fraction")
+	assert.True(t, errors.As(err, &ive))
+
+	_, err = cs.IntVal("server.name")
+	assert.True(t, errors.As(err, &ive))
+	assert.EqualValues(t, "server.name", ive.Path)
+
+	_, err = cs.BoolVal("server.maybe")
+	assert.True(t, errors.As(err, &ive))
+	assert.EqualValues(t, "server.maybe", ive.Path)
+
+	_, err = cs.BoolVal("server.level")
+	assert.True(t, errors.As(err, &ive))
+
+	// Strings that strconv.ParseFloat accepts but that are not finite numbers are rejected
+	nonFinite := ca.NewDefaultSelector(ca.ConfigNode{"nan": "NaN", "inf": "-Inf", "infinity": "infinity"}, true, true, ca.SelectorOpts{Coerce: true})
+
+	for _, path := range []string{"nan", "inf", "infinity"} {
+
+		_, err = nonFinite.Float64Val(path)
+		assert.True(t, errors.As(err, &ive), path)
+		assert.Contains(t, err.Error(), "is not a finite number")
+
+		_, err = nonFinite.IntVal(path)
+		assert.True(t, errors.As(err, &ive), path)
+
+		_, err = nonFinite.DurationVal(path)
+		assert.True(t, errors.As(err, &ive), path)
+	}
+
+	var tme ca.TypeMismatchError
+
+	// Objects and arrays are never coerced to scalars
+	_, err = cs.StringVal("server")
+	assert.True(t, errors.As(err, &tme))
+}
+
+func TestPerCallCoercion(t *testing.T) {
+
+	cs := ca.SelectorFromPathValues(stringConfig())
+
+	i, err := cs.IntVal("server.port", ca.Opts{Coerce: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8080, i)
+
+	b, err := cs.BoolVal("server.debug", ca.Opts{Coerce: true})
+	assert.Nil(t, err)
+	assert.True(t, b)
+
+	p, err := ca.Get[uint16](cs, "server.port", ca.Opts{Coerce: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8080, p)
+
+	var target bool
+	assert.Nil(t, ca.Decode("server.tls", cs.Config(), &target, ca.Opts{Coerce: true}))
+	assert.False(t, target)
+
+	// Coercion only applies to the call it was requested for
+	_, err = cs.IntVal("server.port")
+	assert.NotNil(t, err)
+
+	qs := ca.NewDeferredErrorQuietSelector(cs, func(path string, err error) {
+		t.Errorf("unexpected error at %s: %s", path, err)
+	})
+
+	assert.EqualValues(t, 0.75, qs.Float64Val("server.ratio", ca.Opts{Coerce: true}))
+}
@@ -0,0 +1,529 @@
+package config_access
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Converters is a registry of functions that convert values found in configuration into specific Go types. Converters
+// are consulted before the package's built-in conversions, so they can be used both to support additional types and to
+// change how a type is interpreted. Register converters with RegisterConverter.
+type Converters struct {
+	byType map[reflect.Type]func(interface{}) (interface{}, error)
+}
+
+// NewConverters creates an empty converter registry
+func NewConverters() *Converters {
+	return &Converters{byType: make(map[reflect.Type]func(interface{}) (interface{}, error))}
+}
+
+// RegisterConverter adds a function to the supplied registry that converts a value found in configuration (a string,
+// float64, bool, []interface{} or ConfigNode) into a T. Any existing converter for T is replaced.
+func RegisterConverter[T any](c *Converters, f func(interface{}) (T, error)) {
+	c.byType[reflect.TypeOf((*T)(nil)).Elem()] = func(v interface{}) (interface{}, error) {
+		return f(v)
+	}
+}
+
+func (c *Converters) converter(t reflect.Type) (func(interface{}) (interface{}, error), bool) {
+
+	if c == nil {
+		return nil, false
+	}
+
+	f, found := c.byType[t]
+
+	return f, found
+}
+
+// builtinConversions are the conversions for types that are not represented directly in configuration but are
+// supported without a registered converter
+var builtinConversions = map[reflect.Type]func(c conversion, path string, v interface{}) (interface{}, error){
+	reflect.TypeOf(time.Duration(0)): convertDuration,
+	reflect.TypeOf(time.Time{}):      convertTime,
+	byteSizeType:                     convertByteSize,
+	reflect.TypeOf(url.URL{}):        networkConversion("URL", parseAbsoluteURL),
+	reflect.TypeOf(HostPort{}):       networkConversion("host:port", ParseHostPort),
+	reflect.TypeOf(netip.Addr{}):     networkConversion("IP address", netip.ParseAddr),
+	reflect.TypeOf(netip.Prefix{}):   networkConversion("CIDR", netip.ParsePrefix),
+	reflect.TypeOf(Secret{}):         convertSecret,
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// builtinType returns true if the supplied type, or the type it points to, has a built-in conversion
+func builtinType(t reflect.Type) bool {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	_, found := builtinConversions[t]
+
+	return found
+}
+
+// conversion holds the options that affect how values are converted to Go types during a single access
+type conversion struct {
+	separator   string
+	converters  []*Converters
+	timeLayouts []string
+	coerce      bool
+	// If set, references in strings are expanded before they are converted
+	interpolator *interpolator
+	// Used to resolve strings of the form scheme:reference before they are converted
+	resolvers []*Resolvers
+	// The config that absolute paths in config tags are relative to
+	root ConfigNode
+	// The struct field being populated, used to describe conversion failures
+	field string
+	// If set, keys that do not map to a field of the struct being populated are reported
+	strict bool
+}
+
+// conversion returns the options for converting values found in the supplied config, which is the config used to
+// expand references if interpolation is enabled
+func (a accessor) conversion(node ConfigNode, opts Opts) conversion {
+
+	c := conversion{
+		separator:   a.separator(),
+		converters:  []*Converters{opts.Converters, a.converters},
+		timeLayouts: append(append([]string{}, opts.TimeLayouts...), a.timeLayouts...),
+		coerce:      a.coerce || opts.Coerce,
+		root:        node,
+		strict:      opts.Strict,
+	}
+
+	if a.interpolate || opts.Interpolate {
+		c.interpolator = newInterpolator(node, a, opts)
+	}
+
+	if opts.Resolvers != nil {
+		c.resolvers = append(c.resolvers, opts.Resolvers)
+	}
+
+	c.resolvers = append(c.resolvers, a.resolvers...)
+
+	return c
+}
+
+// convert converts a value found at the supplied path to the supplied type. Objects can be converted to maps (whose
+// keys are converted from strings as described in mapKey) or to structs, arrays to slices or to Go arrays of the same
+// length and scalars to the Go types of the same kind (including named types
+// such as type LogLevel string). Durations, times, sizes and network types are interpreted as described in
+// DurationVal, TimeVal, ByteSizeVal, URLVal, HostPortVal, IPVal and CIDRVal. Other types that implement
+// encoding.TextUnmarshaler or json.Unmarshaler are unmarshalled. Structs are populated as described in Populate. Every
+// element of an array, member of an object and field of a struct is converted, even if some of them fail, so that the
+// returned error reports every problem.
+func (c conversion) convert(path string, v interface{}, t reflect.Type) (reflect.Value, error) {
+
+	if c.expandsStrings() {
+
+		var err error
+
+		if s, found := v.(string); found {
+			v, err = c.expandString(path, s)
+		} else if t.Kind() == reflect.Interface || unmarshals(t) {
+			// The value is used as it is, so every string it contains is expanded
+			v, err = c.expandAll(path, v)
+		}
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	return c.convertValue(path, v, t)
+}
+
+// convertValue converts a value in which any references have already been expanded or resolved
+func (c conversion) convertValue(path string, v interface{}, t reflect.Type) (reflect.Value, error) {
+
+	for _, cs := range c.converters {
+		if f, found := cs.converter(t); found {
+			return c.custom(path, v, t, f)
+		}
+	}
+
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			return reflect.Zero(t), nil
+		}
+
+		return reflect.Value{}, c.mismatch(path, v, t)
+	}
+
+	out := reflect.New(t).Elem()
+
+	if f, found := builtinConversions[t]; found {
+
+		cv, err := f(c, path, v)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out.Set(reflect.ValueOf(cv))
+
+		return out, nil
+	}
+
+	if unmarshals(t) {
+		return c.unmarshal(path, v, t)
+	}
+
+	if c.coerce {
+
+		var err error
+
+		if v, err = coerce(path, v, t); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if reflect.TypeOf(v).Implements(t) {
+			out.Set(reflect.ValueOf(v))
+			return out, nil
+		}
+	case reflect.Pointer:
+		e, err := c.convertValue(path, v, t.Elem())
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out.Set(reflect.New(t.Elem()))
+		out.Elem().Set(e)
+
+		return out, nil
+	case reflect.String:
+		if s, found := v.(string); found {
+			out.SetString(s)
+			return out, nil
+		}
+	case reflect.Bool:
+		if b, found := v.(bool); found {
+			out.SetBool(b)
+			return out, nil
+		}
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return convertInteger(path, v, t)
+	case reflect.Float64, reflect.Float32:
+		if f, found := numberValue(v); found {
+
+			if out.OverflowFloat(f) {
+				return reflect.Value{}, OutOfRangeError{Path: path, Expected: t.String(), Value: v}
+			}
+
+			out.SetFloat(f)
+			return out, nil
+		}
+	case reflect.Slice:
+		if a, found := v.([]interface{}); found {
+			return c.slice(path, a, t)
+		}
+	case reflect.Array:
+		if a, found := v.([]interface{}); found {
+
+			if len(a) != t.Len() {
+				return reflect.Value{}, InvalidValueError{Path: path, Expected: t.String(), Err: fmt.Errorf("the array has %d elements", len(a))}
+			}
+
+			return c.slice(path, a, t)
+		}
+	case reflect.Map:
+		if o, found := object(v); found && c.keyType(t.Key()) {
+			return c.mapOf(path, o, t)
+		}
+	case reflect.Struct:
+		if o, found := object(v); found {
+			return out, c.populateStruct(path, o, out)
+		}
+	}
+
+	return reflect.Value{}, c.mismatch(path, v, t)
+}
+
+// hasConverter returns true if values are converted to the supplied type by a registered converter or by the type's
+// own UnmarshalText or UnmarshalJSON method
+func (c conversion) hasConverter(t reflect.Type) bool {
+
+	for _, cs := range c.converters {
+		if _, found := cs.converter(t); found {
+			return true
+		}
+	}
+
+	return unmarshals(t) && !builtinType(t)
+}
+
+// unmarshals returns true if a pointer to the supplied type implements encoding.TextUnmarshaler or json.Unmarshaler
+func unmarshals(t reflect.Type) bool {
+
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface {
+		return false
+	}
+
+	pt := reflect.PointerTo(t)
+
+	return pt.Implements(textUnmarshalerType) || pt.Implements(jsonUnmarshalerType)
+}
+
+// unmarshal converts a value to a type that implements encoding.TextUnmarshaler or json.Unmarshaler. Strings are passed
+// to UnmarshalText if the type implements it. Otherwise the value is marshalled as JSON and passed to UnmarshalJSON.
+func (c conversion) unmarshal(path string, v interface{}, t reflect.Type) (reflect.Value, error) {
+
+	out := reflect.New(t)
+
+	tu, isText := out.Interface().(encoding.TextUnmarshaler)
+	ju, isJSON := out.Interface().(json.Unmarshaler)
+
+	if _, found := v.(string); !found && isText && !isJSON && c.coerce {
+
+		var err error
+
+		if v, err = coerce(path, v, stringType); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	var err error
+
+	if s, found := v.(string); found && isText {
+		err = tu.UnmarshalText([]byte(s))
+	} else if isJSON {
+
+		var b []byte
+
+		if b, err = json.Marshal(v); err == nil {
+			err = ju.UnmarshalJSON(b)
+		}
+	} else {
+		return reflect.Value{}, TypeMismatchError{Path: path, Expected: t.String(), Actual: kindOf(v)}
+	}
+
+	if err != nil {
+		return reflect.Value{}, InvalidValueError{Path: path, Expected: t.String(), Err: err}
+	}
+
+	return out.Elem(), nil
+}
+
+func (c conversion) custom(path string, v interface{}, t reflect.Type, f func(interface{}) (interface{}, error)) (reflect.Value, error) {
+
+	cv, err := f(v)
+
+	if err != nil {
+		return reflect.Value{}, InvalidValueError{Path: path, Expected: t.String(), Err: err}
+	}
+
+	rv := reflect.ValueOf(cv)
+
+	if !rv.IsValid() {
+		return reflect.Zero(t), nil
+	}
+
+	out := reflect.New(t).Elem()
+	out.Set(rv)
+
+	return out, nil
+}
+
+// slice converts the elements of an array to a slice or Go array of the supplied type, which must have the same length
+// as the array
+func (c conversion) slice(path string, a []interface{}, t reflect.Type) (reflect.Value, error) {
+
+	out := reflect.New(t).Elem()
+
+	if t.Kind() == reflect.Slice {
+		out = reflect.MakeSlice(t, len(a), len(a))
+	}
+
+	var errs []error
+
+	for i, e := range a {
+
+		ec := c
+		ec.field = elementPath(c.field, i)
+
+		ev, err := ec.convert(elementPath(path, i), e, t.Elem())
+
+		if err != nil {
+			errs = append(errs, ec.elementError(elementPath(path, i), err))
+			continue
+		}
+
+		out.Index(i).Set(ev)
+	}
+
+	if len(errs) > 0 {
+		return reflect.Value{}, errors.Join(errs...)
+	}
+
+	return out, nil
+}
+
+func (c conversion) mapOf(path string, o ConfigNode, t reflect.Type) (reflect.Value, error) {
+
+	out := reflect.MakeMapWithSize(t, len(o))
+
+	keys := make([]string, 0, len(o))
+
+	for k := range o {
+		keys = append(keys, k)
+	}
+
+	// Sorted so that errors are reported in a consistent order
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, k := range keys {
+
+		ec := c
+		ec.field = fmt.Sprintf("%s[%q]", c.field, k)
+
+		kv, err := ec.mapKey(c.memberPath(path, k), k, t.Key())
+
+		if err != nil {
+			errs = append(errs, ec.elementError(c.memberPath(path, k), err))
+			continue
+		}
+
+		ev, err := ec.convert(c.memberPath(path, k), o[k], t.Elem())
+
+		if err != nil {
+			errs = append(errs, ec.elementError(c.memberPath(path, k), err))
+			continue
+		}
+
+		out.SetMapIndex(kv, ev)
+	}
+
+	if len(errs) > 0 {
+		return reflect.Value{}, errors.Join(errs...)
+	}
+
+	return out, nil
+}
+
+// keyType returns true if the supplied type can be used as the key of a map populated from an object: a string, number
+// or bool type, or a comparable type with a registered converter or an UnmarshalText or UnmarshalJSON method
+func (c conversion) keyType(t reflect.Type) bool {
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float64, reflect.Float32,
+		reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return true
+	}
+
+	return t.Comparable() && c.hasConverter(t)
+}
+
+// mapKey converts the key of a member of an object to the key type of a map. Keys are converted in the same way as
+// values, except that they are not expanded or resolved and are always coerced to numbers and bools.
+func (c conversion) mapKey(path, key string, t reflect.Type) (reflect.Value, error) {
+
+	kc := c
+	kc.coerce = true
+
+	return kc.convertValue(path, key, t)
+}
+
+// elementError identifies the element of a struct field's value that could not be converted, so that it is named in
+// the PopulateError for the struct
+func (c conversion) elementError(path string, err error) error {
+
+	if c.field == "" {
+		return err
+	}
+
+	return PopulateError{Fields: c.fieldErrors(path, err)}
+}
+
+// memberPath returns the path of a member of the object at the supplied path
+func (c conversion) memberPath(path, key string) string {
+
+	if path == "" {
+		return quoteKey(key, c.separator)
+	}
+
+	return path + c.separator + quoteKey(key, c.separator)
+}
+
+func (c conversion) mismatch(path string, v interface{}, t reflect.Type) error {
+	return TypeMismatchError{Path: path, Expected: t.String(), Actual: kindOf(v)}
+}
+
+// fieldKey returns the name of the object member that a struct field is populated from and whether that name was
+// set explicitly with a tag
+func fieldKey(f reflect.StructField) (string, bool) {
+
+	if tag, found := f.Tag.Lookup("json"); found {
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		if name != "" {
+			return name, true
+		}
+	}
+
+	return f.Name, false
+}
+
+// memberKey finds the key in the object that matches the supplied name, preferring an exact match
+func memberKey(o ConfigNode, name string) (string, bool) {
+
+	if _, found := o[name]; found {
+		return name, true
+	}
+
+	for k := range o {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+
+	return "", false
+}
+
+// object returns the supplied value as a ConfigNode if it is an object
+func object(v interface{}) (ConfigNode, bool) {
+
+	switch t := v.(type) {
+	case ConfigNode:
+		return t, true
+	case map[interface{}]interface{}:
+		return stringKeys(t), true
+	}
+
+	return nil, false
+}
+
+// onMissing returns the value supplied in Opts.OnMissing if it is of the requested type
+func onMissing[T any](path string, def interface{}) (T, error) {
+
+	if v, found := def.(T); found {
+		return v, nil
+	}
+
+	var zero T
+
+	return zero, TypeMismatchError{Path: path, Expected: fmt.Sprintf("%T", zero), Actual: fmt.Sprintf("%T", def)}
+}
@@ -0,0 +1,240 @@
+package config_access
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileOrder determines the order in which configuration files found in a directory are merged.
+type FileOrder int
+
+const (
+	// OrderLexical merges files in the lexical order of their paths relative to the directory being loaded
+	OrderLexical FileOrder = iota
+	// OrderNumericPrefix merges files without a numeric prefix first (in lexical order), then files whose names start
+	// with a number (e.g. 10-db.yaml) in ascending numeric order. Subdirectories are ordered by the same rule.
+	OrderNumericPrefix
+)
+
+// DirOpts defines optional behaviour when loading a directory of configuration files
+type DirOpts struct {
+	// The rule used to order files before they are merged. Ignored if Sort is set.
+	Order FileOrder
+	// If set, this function is used to sort the slash separated paths (relative to the directory) of the files found
+	// instead of the rule defined in Order
+	Sort func(files []string)
+	// If set, arrays in later files are appended to arrays at the same path in earlier files rather than replacing them
+	MergeArrays bool
+	// Options for the Selector returned by LoadDir and LoadFS, which return an InvalidSeparatorError if they are not
+	// valid. Ignored by DirSource (see Loader.Selector).
+	Selector SelectorOpts
+}
+
+// LoadDir finds every JSON and YAML file (.json, .yaml or .yml) in the supplied directory and its subdirectories,
+// orders them according to the supplied options and merges them into a single Selector. Files and directories whose
+// names start with a . are ignored.
+//
+// The paths of the files that were merged are returned in the order they were merged.
+func LoadDir(dir string, o ...DirOpts) (Selector, []string, error) {
+
+	name := dirFileName(dir)
+	opts := dirOptions(o)
+
+	if err := opts.Selector.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	node, files, err := loadFS(os.DirFS(dir), ".", opts, name)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, f := range files {
+		files[i] = name(f)
+	}
+
+	return NewDefaultSelector(node, true, true, opts.Selector), files, nil
+}
+
+// LoadFS behaves like LoadDir, but finds files under the root directory of the supplied fs.FS (e.g. an embed.FS).
+// The returned file paths are the slash separated paths within fsys.
+func LoadFS(fsys fs.FS, root string, o ...DirOpts) (Selector, []string, error) {
+
+	opts := dirOptions(o)
+
+	if err := opts.Selector.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	node, files, err := loadFS(fsys, root, opts, func(f string) string {
+		return path.Join(root, f)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewDefaultSelector(node, true, true, opts.Selector), files, nil
+}
+
+// DirSource creates a Source that loads the supplied directory in the same way as LoadDir, so a directory of
+// files can be used as a single layer in a Loader.
+func DirSource(dir string, o ...DirOpts) Source {
+	return &sourceFunc{
+		name: dir,
+		load: func() (ConfigNode, error) {
+			node, _, err := loadFS(os.DirFS(dir), ".", dirOptions(o), dirFileName(dir))
+			return node, err
+		},
+	}
+}
+
+func dirOptions(o []DirOpts) DirOpts {
+	if len(o) == 0 {
+		return DirOpts{}
+	} else {
+		return o[0]
+	}
+}
+
+// dirFileName returns a function that converts the path of a file relative to the supplied directory into a path that
+// includes the directory
+func dirFileName(dir string) func(string) string {
+	return func(f string) string {
+		return filepath.Join(dir, filepath.FromSlash(f))
+	}
+}
+
+// loadFS merges the configuration files under root. name converts the path of a file relative to root into the name
+// used in any ParseError.
+func loadFS(fsys fs.FS, root string, opts DirOpts, name func(string) string) (ConfigNode, []string, error) {
+
+	files, err := findConfigFiles(fsys, root)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Sort != nil {
+		opts.Sort(files)
+	} else if opts.Order == OrderNumericPrefix {
+		sort.SliceStable(files, func(i, j int) bool {
+			return numericPrefixLess(files[i], files[j])
+		})
+	} else {
+		sort.Strings(files)
+	}
+
+	combined := make(ConfigNode)
+	loaded := make([]string, 0, len(files))
+
+	for _, f := range files {
+
+		data, err := fs.ReadFile(fsys, path.Join(root, f))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node, err := Parse(name(f), data, FormatAuto)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		Merge(combined, node, opts.MergeArrays)
+		loaded = append(loaded, f)
+	}
+
+	return combined, loaded, nil
+}
+
+// findConfigFiles returns the paths of JSON and YAML files under root, relative to root
+func findConfigFiles(fsys fs.FS, root string) ([]string, error) {
+
+	var files []string
+
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(path.Ext(p)) {
+		case ".json", ".yaml", ".yml":
+			if root == "." {
+				files = append(files, p)
+			} else {
+				files = append(files, strings.TrimPrefix(p, root+"/"))
+			}
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+func numericPrefixLess(a, b string) bool {
+
+	as := strings.Split(a, "/")
+	bs := strings.Split(b, "/")
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+
+		if as[i] == bs[i] {
+			continue
+		}
+
+		an, aNumbered := numericPrefix(as[i])
+		bn, bNumbered := numericPrefix(bs[i])
+
+		switch {
+		case aNumbered && bNumbered && an != bn:
+			return an < bn
+		case aNumbered != bNumbered:
+			return bNumbered
+		default:
+			return as[i] < bs[i]
+		}
+	}
+
+	return len(as) < len(bs)
+}
+
+func numericPrefix(name string) (uint64, bool) {
+
+	end := strings.IndexFunc(name, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+
+	if end == -1 {
+		end = len(name)
+	}
+
+	if end == 0 {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(name[:end], 10, 64)
+
+	return n, err == nil
+}
@@ -0,0 +1,145 @@
+package config_access_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDirLexical(t *testing.T) {
+
+	s, files, err := ca.LoadDir("testdata/fragments")
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, []string{
+		filepath.Join("testdata", "fragments", "10-db.yaml"),
+		filepath.Join("testdata", "fragments", "2-cache.json"),
+		filepath.Join("testdata", "fragments", "base.json"),
+		filepath.Join("testdata", "fragments", "nested", "20-extra.yml"),
+	}, files)
+
+	last, err := s.StringVal("last")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "20-extra", last)
+
+	host, err := s.StringVal("db.host")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "localhost", host)
+}
+
+func TestLoadDirNumericPrefix(t *testing.T) {
+
+	s, files, err := ca.LoadDir("testdata/fragments", ca.DirOpts{Order: ca.OrderNumericPrefix})
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, []string{
+		filepath.Join("testdata", "fragments", "base.json"),
+		filepath.Join("testdata", "fragments", "nested", "20-extra.yml"),
+		filepath.Join("testdata", "fragments", "2-cache.json"),
+		filepath.Join("testdata", "fragments", "10-db.yaml"),
+	}, files)
+
+	last, err := s.StringVal("last")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "10-db", last)
+
+	host, err := s.StringVal("db.host")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "db.internal", host)
+
+	port, err := s.IntVal("db.port")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5432, port)
+
+	size, err := s.IntVal("cache.size")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, size)
+
+	b, err := s.BoolVal("extra")
+	assert.NoError(t, err)
+	assert.True(t, b)
+}
+
+func TestLoadFSWithCustomSort(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"conf/a.json":       {Data: []byte(`{"v": "a"}`)},
+		"conf/b.yaml":       {Data: []byte("v: b\n")},
+		"conf/.hidden.json": {Data: []byte(`{"v": "hidden"}`)},
+		"conf/notes.txt":    {Data: []byte("ignored")},
+	}
+
+	reverse := func(files []string) {
+		sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	}
+
+	s, files, err := ca.LoadFS(fsys, "conf", ca.DirOpts{Sort: reverse})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"b.yaml", "a.json"}, files)
+
+	v, err := s.StringVal("v")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "a", v)
+
+	fsys["conf/c.json"] = &fstest.MapFile{Data: []byte(`{"v": `)}
+
+	_, _, err = ca.LoadFS(fsys, "conf")
+
+	var pe ca.ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.EqualValues(t, "conf/c.json", pe.File)
+
+	_, _, err = ca.LoadFS(fsys, "missing")
+	assert.Error(t, err)
+}
+
+func TestLoadFSWithSelectorOpts(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"conf/a.json": {Data: []byte(`{"db": {"port": "5432"}}`)},
+	}
+
+	s, _, err := ca.LoadFS(fsys, "conf", ca.DirOpts{Selector: ca.SelectorOpts{Separator: "/", Coerce: true}})
+	assert.NoError(t, err)
+
+	port, err := s.IntVal("db/port")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5432, port)
+}
+
+func TestDirSourceInLoader(t *testing.T) {
+
+	s, err := ca.NewLoader().
+		Add(ca.DirSource("testdata/fragments", ca.DirOpts{Order: ca.OrderNumericPrefix})).
+		Add(ca.DirSource("testdata/no-such-dir"), ca.LayerOpts{Optional: true}).
+		Selector()
+
+	assert.NoError(t, err)
+
+	host, err := s.StringVal("db.host")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "db.internal", host)
+}
+
+func TestLoadDirParseErrorNamesDirectory(t *testing.T) {
+
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"v": `), 0o600))
+
+	_, _, err := ca.LoadDir(dir)
+
+	var pe ca.ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.EqualValues(t, filepath.Join(dir, "bad.json"), pe.File)
+
+	_, err = ca.DirSource(dir).Load()
+	assert.True(t, errors.As(err, &pe))
+	assert.EqualValues(t, filepath.Join(dir, "bad.json"), pe.File)
+}
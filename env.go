@@ -0,0 +1,184 @@
+package config_access
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvCase controls how the elements of an environment variable's name are converted to configuration keys
+type EnvCase int
+
+const (
+	// EnvLowerCase converts each element to lower case (MAX_CONNECTIONS becomes max_connections)
+	EnvLowerCase EnvCase = iota
+	// EnvCamelCase converts each element to camel case, treating single underscores as word boundaries
+	// (MAX_CONNECTIONS becomes maxConnections)
+	EnvCamelCase
+	// EnvPreserveCase uses each element unchanged
+	EnvPreserveCase
+)
+
+// DefaultEnvSeparator separates the elements of a path in an environment variable's name (APP_DATABASE__HOST)
+const DefaultEnvSeparator = "__"
+
+// EnvOpts defines how environment variables are converted to configuration by EnvSource and EnvNode
+type EnvOpts struct {
+	// If set, only variables whose names start with the prefix (e.g. APP_) are used and the prefix is removed from
+	// the name before it is converted to a path. The prefix is case-sensitive.
+	Prefix string
+	// The string that separates the elements of a path in a variable's name. If not set, DefaultEnvSeparator is used.
+	Separator string
+	// How each element of a name is converted to a key. Defaults to EnvLowerCase.
+	Case EnvCase
+	// If true, values that are valid JSON numbers, bools, arrays or objects (e.g. 8080, true or ["a","b"]) are
+	// converted to the equivalent configuration value. Otherwise all values are strings.
+	InferTypes bool
+	// If set, these variables are used instead of the process environment (e.g. for testing)
+	Vars map[string]string
+}
+
+// EnvSource creates a Source that converts environment variables into a layer of configuration, so that they can be
+// merged on top of configuration loaded from files. With a prefix of APP_, the variable APP_DATABASE__HOST is converted to
+// the path database.host.
+//
+// Variables whose names are empty after the prefix is removed or that contain empty elements (APP_DATABASE____HOST) are
+// ignored, as are variables with an element made only of underscores when using EnvCamelCase (APP_DATABASE___). If one variable's path is a prefix of another's (APP_DATABASE and APP_DATABASE__HOST), the longer path
+// replaces the shorter.
+func EnvSource(o ...EnvOpts) Source {
+	return &sourceFunc{
+		name: "environment",
+		load: func() (ConfigNode, error) {
+			return EnvNode(o...), nil
+		},
+	}
+}
+
+// EnvNode converts environment variables to a ConfigNode, as described in EnvSource
+func EnvNode(o ...EnvOpts) ConfigNode {
+
+	var opts EnvOpts
+
+	if len(o) > 0 {
+		opts = o[0]
+	}
+
+	vars := opts.Vars
+
+	if vars == nil {
+		vars = environment()
+	}
+
+	separator := opts.Separator
+
+	if separator == "" {
+		separator = DefaultEnvSeparator
+	}
+
+	names := make([]string, 0, len(vars))
+
+	for name := range vars {
+		if strings.HasPrefix(name, opts.Prefix) {
+			names = append(names, name)
+		}
+	}
+
+	// Sorted so that longer paths consistently replace shorter paths, as with SelectorFromPathValues
+	sort.Strings(names)
+
+	node := make(ConfigNode)
+
+	for _, name := range names {
+
+		if keys, valid := envKeys(strings.TrimPrefix(name, opts.Prefix), separator, opts.Case); valid {
+			addValue(keys, envValue(vars[name], opts.InferTypes), node)
+		}
+	}
+
+	return node
+}
+
+func environment() map[string]string {
+
+	vars := make(map[string]string)
+
+	for _, v := range os.Environ() {
+		if name, value, found := strings.Cut(v, "="); found && name != "" {
+			vars[name] = value
+		}
+	}
+
+	return vars
+}
+
+func envKeys(name, separator string, c EnvCase) ([]string, bool) {
+
+	if name == "" {
+		return nil, false
+	}
+
+	keys := strings.Split(name, separator)
+
+	for i, k := range keys {
+
+		if k == "" {
+			return nil, false
+		}
+
+		switch c {
+		case EnvLowerCase:
+			keys[i] = strings.ToLower(k)
+		case EnvCamelCase:
+			keys[i] = camelCase(k)
+		}
+
+		// An element made only of underscores has no words, so would be an empty key that no path can reach
+		if keys[i] == "" {
+			return nil, false
+		}
+	}
+
+	return keys, true
+}
+
+func camelCase(s string) string {
+
+	var b strings.Builder
+
+	for i, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' }) {
+
+		word = strings.ToLower(word)
+
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+
+		b.WriteString(word)
+	}
+
+	return b.String()
+}
+
+func envValue(value string, infer bool) interface{} {
+
+	if !infer {
+		return value
+	}
+
+	trimmed := strings.TrimSpace(value)
+
+	var parsed interface{}
+
+	if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
+		return value
+	}
+
+	switch parsed.(type) {
+	case float64, bool, []interface{}, map[string]interface{}:
+		return parsed
+	default:
+		// Quoted strings and null are left as they were written
+		return value
+	}
+}
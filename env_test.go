@@ -0,0 +1,119 @@
+package config_access_test
+
+import (
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEnvironment() map[string]string {
+	return map[string]string{
+		"APP_DATABASE__HOST":            "db.internal",
+		"APP_DATABASE__PORT":            "5433",
+		"APP_DATABASE__MAX_CONNECTIONS": "50",
+		"APP_FEATURES__BETA":            "true",
+		"APP_FEATURES__REGIONS":         `["eu", "us"]`,
+		"APP_GREETING":                  `"quoted"`,
+		"APP_ZIP":                       "01234",
+		"APP_BROKEN____KEY":             "ignored",
+		"APP_":                          "ignored",
+		"OTHER_DATABASE__HOST":          "ignored",
+		"APP_LOG":                       "replaced",
+		"APP_LOG__LEVEL":                "debug",
+	}
+}
+
+func TestEnvNodeDefaults(t *testing.T) {
+
+	node := ca.EnvNode(ca.EnvOpts{Prefix: "APP_", Vars: testEnvironment()})
+
+	cs := ca.NewDefaultSelector(node, true, true)
+
+	s, err := cs.StringVal("database.host")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "db.internal", s)
+
+	// Without type inference, every value is a string
+	s, err = cs.StringVal("database.port")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "5433", s)
+
+	assert.True(t, cs.PathExists("database.max_connections"))
+	assert.True(t, cs.PathExists("log.level"))
+	assert.False(t, cs.PathExists("broken"))
+	assert.False(t, cs.PathExists("other"))
+	assert.Len(t, node, 5)
+}
+
+func TestEnvNodeOptions(t *testing.T) {
+
+	node := ca.EnvNode(ca.EnvOpts{Prefix: "APP_", Vars: testEnvironment(), Case: ca.EnvCamelCase, InferTypes: true})
+
+	cs := ca.NewDefaultSelector(node, true, true)
+
+	i, err := cs.IntVal("database.maxConnections")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 50, i)
+
+	b, err := cs.BoolVal("features.beta")
+	assert.Nil(t, err)
+	assert.True(t, b)
+
+	r, err := cs.StringArray("features.regions")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"eu", "us"}, r)
+
+	// Quoted strings and values that are not valid JSON are left unchanged
+	s, err := cs.StringVal("greeting")
+	assert.Nil(t, err)
+	assert.EqualValues(t, `"quoted"`, s)
+
+	s, err = cs.StringVal("zip")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "01234", s)
+
+	node = ca.EnvNode(ca.EnvOpts{Vars: map[string]string{"Db_Host": "x", "Db_Port": "1"}, Separator: "_", Case: ca.EnvPreserveCase})
+	assert.EqualValues(t, ca.ConfigNode{"Db": ca.ConfigNode{"Host": "x", "Port": "1"}}, node)
+}
+
+func TestEnvCamelCaseUnderscoreElements(t *testing.T) {
+
+	vars := map[string]string{"APP_DB__MAX_SIZE": "5", "APP_DB___": "x", "APP___": "y", "APP_X____Y": "z"}
+
+	// Elements made only of underscores would become empty keys, so the variables are ignored
+	node := ca.EnvNode(ca.EnvOpts{Prefix: "APP_", Vars: vars, Case: ca.EnvCamelCase})
+	assert.EqualValues(t, ca.ConfigNode{"db": ca.ConfigNode{"maxSize": "5"}}, node)
+}
+
+func TestEnvSourceOverlay(t *testing.T) {
+
+	base := ca.ConfigNode{"database": ca.ConfigNode{"host": "localhost", "port": 5432.0, "name": "app"}}
+
+	l := ca.NewLoader().
+		Add(ca.NodeSource("defaults", base)).
+		Add(ca.EnvSource(ca.EnvOpts{Prefix: "APP_", Vars: testEnvironment(), InferTypes: true}))
+
+	cs, err := l.Selector()
+	assert.Nil(t, err)
+
+	s, _ := cs.StringVal("database.host")
+	assert.EqualValues(t, "db.internal", s)
+
+	p, _ := cs.IntVal("database.port")
+	assert.EqualValues(t, 5433, p)
+
+	n, _ := cs.StringVal("database.name")
+	assert.EqualValues(t, "app", n)
+
+	assert.EqualValues(t, "environment", ca.EnvSource().Name())
+}
+
+func TestEnvSourceUsesProcessEnvironment(t *testing.T) {
+
+	t.Setenv("CA_TEST_SERVER__PORT", "9000")
+
+	node, err := ca.EnvSource(ca.EnvOpts{Prefix: "CA_TEST_"}).Load()
+	assert.Nil(t, err)
+	assert.EqualValues(t, ca.ConfigNode{"server": ca.ConfigNode{"port": "9000"}}, node)
+}
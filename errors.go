@@ -0,0 +1,381 @@
+package config_access
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathError indicates that there is no value at a path. It identifies the element of the path that could not be
+// matched and the kind of value that element was applied to.
+type PathError struct {
+	// The path that was requested
+	Path string
+	// The element of the path that could not be matched (e.g. host or [2])
+	Segment string
+	// The concrete path to the value that Segment was applied to. Empty if Segment is the first element of the path.
+	Parent string
+	// The kind of value found at Parent: object, array, string, number, bool or null (or the Go type of any other value)
+	Found string
+
+	missing  bool
+	expected string
+}
+
+func (pe PathError) Error() string {
+
+	parent := "the root"
+
+	if pe.Parent != "" {
+		parent = pe.Parent
+	}
+
+	if pe.missing {
+		return fmt.Sprintf("no value found at %s: the %s at %s has no element %s", pe.Path, pe.Found, parent, pe.Segment)
+	}
+
+	return fmt.Sprintf("no value found at %s: element %s cannot be applied to the %s at %s", pe.Path, pe.Segment, pe.Found, parent)
+}
+
+// Unwrap returns a MissingPathError if the path stopped matching because of a missing or null value, otherwise a
+// TypeMismatchError describing the value that the unmatched element could not be applied to.
+func (pe PathError) Unwrap() error {
+
+	if pe.missing {
+		return MissingPathError{Path: pe.Path}
+	}
+
+	return TypeMismatchError{Path: pe.Parent, Expected: pe.expected, Actual: pe.Found}
+}
+
+// Missing returns true if the value at Parent was an object or array that could contain Segment, but there was no
+// element matching Segment or the matching element was null. Returns false if the value at Parent is of a type that
+// Segment cannot be applied to (e.g. a string or an object when Segment is an array index).
+func (pe PathError) Missing() bool {
+	return pe.missing
+}
+
+func newPathError(path string, segments []pathSegment, stop *pathStop, separator string) PathError {
+	return PathError{
+		Path:     path,
+		Segment:  segmentText(segments[stop.position], separator),
+		Parent:   buildPath(separator, stop.elements...),
+		Found:    kindOf(stop.found),
+		missing:  stop.missing,
+		expected: expectedKind(segments[stop.position]),
+	}
+}
+
+// expectedKind returns the kind of value that a segment can be applied to
+func expectedKind(seg pathSegment) string {
+
+	if seg.kind == keySegment {
+
+		if _, err := strconv.Atoi(seg.key); err == nil {
+			return "object or array"
+		}
+
+		return "object"
+	}
+
+	return "array"
+}
+
+// kindOf describes the kind of a config value in the terms used by JSON
+func kindOf(v interface{}) string {
+
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, json.Number:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// ErrNilConfig is returned when a nil ConfigNode is supplied to a function that accesses config values
+var ErrNilConfig = errors.New("supplied ConfigNode is nil")
+
+// MissingPathError indicates that the a problem was caused by there being no value at the supplied
+// config path
+type MissingPathError struct {
+	Path string
+}
+
+func (mp MissingPathError) Error() string {
+	return "no value found at " + mp.Path
+}
+
+// TypeMismatchError indicates that the value at a path is not of a type that can be converted to the type that was
+// requested.
+type TypeMismatchError struct {
+	Path string
+	// The type that was requested (e.g. string, int or object)
+	Expected string
+	// The kind of value found at the path: object, array, string, number, bool or null (or the Go type of any other value)
+	Actual string
+}
+
+func (tm TypeMismatchError) Error() string {
+	return fmt.Sprintf("value at %s is %s and cannot be converted to %s", tm.Path, article(tm.Actual), article(tm.Expected))
+}
+
+// InvalidValueError indicates that the value at a path is of the right kind, but its content cannot be interpreted as
+// the requested type (e.g. a string that is not a valid duration).
+type InvalidValueError struct {
+	Path string
+	// The type that was requested
+	Expected string
+	// The reason the value could not be converted
+	Err error
+}
+
+func (iv InvalidValueError) Error() string {
+	return fmt.Sprintf("value at %s cannot be converted to %s: %s", iv.Path, article(iv.Expected), iv.Err.Error())
+}
+
+func (iv InvalidValueError) Unwrap() error {
+	return iv.Err
+}
+
+// OutOfRangeError indicates that the number at a path is outside the range of the numeric type that was requested
+type OutOfRangeError struct {
+	Path string
+	// The type that was requested (e.g. int8, uint or float32)
+	Expected string
+	// The value found at the path
+	Value interface{}
+}
+
+func (or OutOfRangeError) Error() string {
+	return fmt.Sprintf("value at %s (%v) is outside the range of %s", or.Path, or.Value, article(or.Expected))
+}
+
+// EnvVarUnsetError indicates that the value at a path refers to an environment variable that is not set
+type EnvVarUnsetError struct {
+	Path string
+	// The name of the environment variable
+	Name string
+}
+
+func (ev EnvVarUnsetError) Error() string {
+	return fmt.Sprintf("environment variable %s (referenced at %s) is not set", ev.Name, ev.Path)
+}
+
+// InterpolationError indicates that a reference of the form ${...} in a string could not be expanded
+type InterpolationError struct {
+	// The path of the value containing the reference. Empty if the string was not read from config.
+	Path string
+	// The reference that could not be expanded (e.g. DB_PASS or database.host)
+	Reference string
+	// The reason the reference could not be expanded
+	Err error
+}
+
+func (ie InterpolationError) Error() string {
+
+	if ie.Path == "" {
+		return fmt.Sprintf("unable to expand ${%s}: %s", ie.Reference, ie.Err.Error())
+	}
+
+	return fmt.Sprintf("unable to expand ${%s} in value at %s: %s", ie.Reference, ie.Path, ie.Err.Error())
+}
+
+func (ie InterpolationError) Unwrap() error {
+	return ie.Err
+}
+
+// ResolverError indicates that a Resolver could not resolve the reference in the value at a path
+type ResolverError struct {
+	Path string
+	// The scheme of the reference (e.g. file)
+	Scheme string
+	// The error returned by the Resolver
+	Err error
+}
+
+func (re ResolverError) Error() string {
+	return fmt.Sprintf("unable to resolve %s: reference in value at %s: %s", re.Scheme, re.Path, re.Err.Error())
+}
+
+func (re ResolverError) Unwrap() error {
+	return re.Err
+}
+
+// PathSyntaxError indicates that a path or query could not be parsed
+type PathSyntaxError struct {
+	Path   string
+	Reason string
+}
+
+func (ps PathSyntaxError) Error() string {
+	return fmt.Sprintf("invalid path %s: %s", ps.Path, ps.Reason)
+}
+
+// InvalidSeparatorError indicates that a path separator contains characters that have a special meaning in paths
+type InvalidSeparatorError struct {
+	Separator string
+}
+
+func (is InvalidSeparatorError) Error() string {
+	return fmt.Sprintf("invalid path separator %q: must not contain any of %s", is.Separator, reservedPathChars)
+}
+
+// UnsupportedFieldError indicates that a struct field is of a type that cannot be populated from config
+type UnsupportedFieldError struct {
+	Path  string
+	Field string
+	// The Go type of the field
+	Type string
+}
+
+func (uf UnsupportedFieldError) Error() string {
+	return fmt.Sprintf("unable to use value at %s as target field %s is not a supported type (%s)", uf.Path, uf.Field, uf.Type)
+}
+
+// UnknownKeyError indicates that a struct was populated in strict mode (see Opts.Strict) and the value at a path does
+// not map to any of its fields
+type UnknownKeyError struct {
+	Path string
+	// The Go type of the struct being populated
+	Type string
+}
+
+func (uk UnknownKeyError) Error() string {
+	return fmt.Sprintf("value at %s does not map to a field of %s", uk.Path, uk.Type)
+}
+
+// FieldError describes a struct field that could not be populated from the value at a path
+type FieldError struct {
+	Path string
+	// The name of the field, qualified by the names of any enclosing fields (e.g. Database.Port or Backends[1].Host).
+	// Empty if the error applies to the target as a whole.
+	Field string
+	// The reason the field could not be populated
+	Err error
+}
+
+func (fe FieldError) Error() string {
+
+	if fe.Field == "" {
+		return fmt.Sprintf("at %s: %s", fe.Path, fe.Err.Error())
+	}
+
+	return fmt.Sprintf("field %s at %s: %s", fe.Field, fe.Path, fe.Err.Error())
+}
+
+func (fe FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// PopulateError is returned by Populate, PopulateFromRoot and SetField and lists every field that could not be
+// populated. errors.As and errors.Is examine each of the FieldErrors, so errors.As can be used to find, for example, a
+// TypeMismatchError.
+type PopulateError struct {
+	Fields []FieldError
+}
+
+func (pe PopulateError) Error() string {
+
+	var b strings.Builder
+
+	if len(pe.Fields) == 1 {
+		b.WriteString("unable to populate 1 field:")
+	} else {
+		fmt.Fprintf(&b, "unable to populate %d fields:", len(pe.Fields))
+	}
+
+	for _, fe := range pe.Fields {
+		b.WriteString("\n  ")
+		b.WriteString(fe.Error())
+	}
+
+	return b.String()
+}
+
+func (pe PopulateError) Unwrap() []error {
+
+	errs := make([]error, len(pe.Fields))
+
+	for i, fe := range pe.Fields {
+		errs[i] = fe
+	}
+
+	return errs
+}
+
+// missing returns true if a lookup failed only because there is no value at the path (or no config)
+func missing(err error) bool {
+
+	var pe PathError
+	var mpe MissingPathError
+
+	switch {
+	case errors.As(err, &pe):
+		return pe.Missing()
+	case errors.As(err, &mpe):
+		return true
+	}
+
+	return errors.Is(err, ErrNilConfig)
+}
+
+// errorPath returns the path carried by one of this package's errors
+func errorPath(err error) (string, bool) {
+
+	switch e := err.(type) {
+	case MissingPathError:
+		return e.Path, true
+	case TypeMismatchError:
+		return e.Path, true
+	case InvalidValueError:
+		return e.Path, true
+	case OutOfRangeError:
+		return e.Path, true
+	case InterpolationError:
+		return e.Path, e.Path != ""
+	case ResolverError:
+		return e.Path, true
+	case UnsupportedFieldError:
+		return e.Path, true
+	case UnknownKeyError:
+		return e.Path, true
+	}
+
+	return "", false
+}
+
+func article(s string) string {
+
+	if s == "" {
+		return s
+	}
+
+	if strings.HasPrefix(s, "uint") {
+		// Pronounced 'you-int'
+		return "a " + s
+	}
+
+	switch s[0] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return "an " + s
+	default:
+		return "a " + s
+	}
+}
+
+// elementPath returns the path of an element of the array at the supplied path
+func elementPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
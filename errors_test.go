@@ -0,0 +1,141 @@
+package config_access_test
+
+import (
+	"errors"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingPathErrors(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "simple.json")
+	yamlConf := loadYamlTestFile(t, "simple.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		_, err := cs.IntVal("simpleOne.Missing")
+
+		var mpe ca.MissingPathError
+		assert.True(t, errors.As(err, &mpe))
+		assert.EqualValues(t, "simpleOne.Missing", mpe.Path)
+
+		var tme ca.TypeMismatchError
+		assert.False(t, errors.As(err, &tme))
+
+		_, err = cs.ObjectVal("missing.object")
+		assert.True(t, errors.As(err, &mpe))
+
+		_, err = cs.StringArray("simpleOne.StringArray[9]")
+		assert.True(t, errors.As(err, &mpe))
+		assert.EqualValues(t, "simpleOne.StringArray[9]", mpe.Path)
+
+		var sc SimpleConfig
+
+		err = ca.Populate("undefined", &sc, node)
+		assert.True(t, errors.As(err, &mpe))
+		assert.EqualValues(t, "undefined", mpe.Path)
+
+		err = ca.SetField("String", "undefined.path", &sc, node)
+		assert.True(t, errors.As(err, &mpe))
+		assert.EqualValues(t, "undefined.path", mpe.Path)
+	}
+}
+
+func TestTypeMismatchErrors(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "simple.json")
+	yamlConf := loadYamlTestFile(t, "simple.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		var tme ca.TypeMismatchError
+
+		_, err := cs.IntVal("simpleOne.String")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, ca.TypeMismatchError{Path: "simpleOne.String", Expected: "int", Actual: "string"}, tme)
+		assert.EqualValues(t, "value at simpleOne.String is a string and cannot be converted to an int", err.Error())
+
+		_, err = cs.BoolVal("simpleOne.Int")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "bool", tme.Expected)
+		assert.EqualValues(t, "number", tme.Actual)
+
+		_, err = cs.ObjectVal("simpleOne.StringArray")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "object", tme.Expected)
+		assert.EqualValues(t, "array", tme.Actual)
+
+		_, err = cs.Array("simpleOne.StringMap")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "array", tme.Expected)
+		assert.EqualValues(t, "object", tme.Actual)
+
+		_, err = cs.IntArray("simpleOne.StringArray")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "simpleOne.StringArray[0]", tme.Path)
+
+		// A path that passes through a scalar
+		_, err = cs.StringVal("simpleOne.String.x")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, ca.TypeMismatchError{Path: "simpleOne.String", Expected: "object", Actual: "string"}, tme)
+
+		var mpe ca.MissingPathError
+		assert.False(t, errors.As(err, &mpe))
+
+		var sc SimpleConfig
+
+		err = ca.SetField("StringMap", "invalidConfig.StringMap", &sc, node)
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "invalidConfig.StringMap.key1", tme.Path)
+
+		var ufe ca.UnsupportedFieldError
+
+		err = ca.SetField("Unsupported", "simpleOne.IntArray", &sc, node)
+		assert.True(t, errors.As(err, &ufe))
+		assert.EqualValues(t, "Unsupported", ufe.Field)
+	}
+}
+
+func TestOtherErrorTypes(t *testing.T) {
+
+	_, err := ca.StringVal("a", nil)
+	assert.True(t, errors.Is(err, ca.ErrNilConfig))
+
+	s := ca.SelectorFromPathValues(map[string]interface{}{"env": "$NOT_SET_ANYWHERE"})
+
+	_, err = s.StringOrEnv("env", ca.Opts{EnvAccessFunc: func(string) string { return "" }})
+
+	var eue ca.EnvVarUnsetError
+	assert.True(t, errors.As(err, &eue))
+	assert.EqualValues(t, "env", eue.Path)
+	assert.EqualValues(t, "NOT_SET_ANYWHERE", eue.Name)
+
+	_, err = s.StringVal("env[")
+
+	var pse ca.PathSyntaxError
+	assert.True(t, errors.As(err, &pse))
+	assert.EqualValues(t, "env[", pse.Path)
+
+	_, err = s.Select("env..")
+	assert.True(t, errors.As(err, &pse))
+}
+
+func TestPopulateErrorMessages(t *testing.T) {
+
+	pe := ca.PopulateError{Fields: []ca.FieldError{
+		{Path: "db.port", Field: "Port", Err: errors.New("not a number")},
+		{Path: "db", Err: ca.ErrNilConfig},
+	}}
+
+	assert.EqualValues(t, "unable to populate 2 fields:\n  field Port at db.port: not a number\n  at db: supplied ConfigNode is nil", pe.Error())
+	assert.True(t, errors.Is(pe, ca.ErrNilConfig))
+
+	pe.Fields = pe.Fields[:1]
+	assert.EqualValues(t, "unable to populate 1 field:\n  field Port at db.port: not a number", pe.Error())
+}
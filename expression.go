@@ -0,0 +1,490 @@
+package config_access
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filterExpression is a compiled filter (the content of [?...] in a query) that is evaluated against each member of
+// an object or element of an array.
+type filterExpression interface {
+	matches(current interface{}) bool
+}
+
+// parseFilter compiles a filter expression. The grammar is:
+//
+//	expression := and ( '||' and )*
+//	and        := unary ( '&&' unary )*
+//	unary      := '!' unary | '(' expression ')' | comparison
+//	comparison := operand ( ( '==' | '!=' | '<' | '<=' | '>' | '>=' ) operand )?
+//	operand    := literal | path
+//	literal    := 'string' | "string" | number | true | false | null
+//
+// Paths are relative to the value being filtered, either as a bare path (role, tags[0]) or starting with @ (@.role,
+// @[0]). @ on its own refers to the value being filtered. An operand without a comparison is true if it refers to a value
+// other than null or false.
+func parseFilter(source string, separator string) (filterExpression, error) {
+
+	tokens, err := tokenise(source, separator)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, separator: separator}
+
+	expr, err := p.expression()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in filter %s", p.peek().text, source)
+	}
+
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	operatorToken tokenKind = iota
+	literalToken
+	pathToken
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{}
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"}
+
+func tokenise(source string, separator string) ([]token, error) {
+
+	var tokens []token
+
+	i := 0
+
+	for i < len(source) {
+
+		c := source[i]
+
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			i++
+			continue
+		}
+
+		if op := operatorAt(source[i:]); op != "" {
+			tokens = append(tokens, token{kind: operatorToken, text: op})
+			i += len(op)
+			continue
+		}
+
+		if c == '\'' || c == '"' {
+
+			s, length, err := quotedString(source[i:])
+
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: literalToken, text: source[i : i+length], value: s})
+			i += length
+			continue
+		}
+
+		end := i
+
+		for end < len(source) {
+
+			e := source[end]
+
+			if e == ' ' || e == '\t' || e == '\n' || e == '\r' || operatorAt(source[end:]) != "" {
+				break
+			}
+
+			if e == '"' {
+				// A quoted key within a path
+				_, length, err := quotedString(source[end:])
+
+				if err != nil {
+					return nil, err
+				}
+
+				end += length
+				continue
+			}
+
+			if e == '[' {
+				closing := closingBracket(source[end:])
+
+				if closing == -1 {
+					return nil, fmt.Errorf("unterminated [ in filter %s", source)
+				}
+
+				end += closing + 1
+				continue
+			}
+
+			end++
+		}
+
+		text := source[i:end]
+
+		if t, isLiteral := literal(text); isLiteral {
+			tokens = append(tokens, token{kind: literalToken, text: text, value: t})
+		} else {
+			tokens = append(tokens, token{kind: pathToken, text: text})
+		}
+
+		i = end
+	}
+
+	return tokens, nil
+}
+
+func operatorAt(s string) string {
+
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// quotedString reads a string enclosed in single or double quotes from the start of the supplied string, returning the
+// unescaped string and the number of bytes consumed
+func quotedString(s string) (string, int, error) {
+
+	quote := s[0]
+
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+
+		switch s[i] {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string %s", s)
+}
+
+// jsonNumber matches the JSON number grammar. Other forms accepted by strconv.ParseFloat (e.g. inf, nan or hex floats)
+// are treated as paths.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func literal(text string) (interface{}, bool) {
+
+	switch text {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+
+	if !jsonNumber.MatchString(text) {
+		return nil, false
+	}
+
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f, true
+	}
+
+	return nil, false
+}
+
+type filterParser struct {
+	tokens    []token
+	position  int
+	separator string
+}
+
+func (p *filterParser) done() bool {
+	return p.position >= len(p.tokens)
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *filterParser) acceptOperator(op string) bool {
+
+	if !p.done() && p.peek().kind == operatorToken && p.peek().text == op {
+		p.position++
+		return true
+	}
+
+	return false
+}
+
+func (p *filterParser) expression() (filterExpression, error) {
+
+	left, err := p.and()
+
+	for err == nil && p.acceptOperator("||") {
+
+		var right filterExpression
+
+		if right, err = p.and(); err == nil {
+			left = orExpression{left: left, right: right}
+		}
+	}
+
+	return left, err
+}
+
+func (p *filterParser) and() (filterExpression, error) {
+
+	left, err := p.unary()
+
+	for err == nil && p.acceptOperator("&&") {
+
+		var right filterExpression
+
+		if right, err = p.unary(); err == nil {
+			left = andExpression{left: left, right: right}
+		}
+	}
+
+	return left, err
+}
+
+func (p *filterParser) unary() (filterExpression, error) {
+
+	if p.acceptOperator("!") {
+
+		e, err := p.unary()
+
+		return notExpression{e}, err
+	}
+
+	if p.acceptOperator("(") {
+
+		e, err := p.expression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.acceptOperator(")") {
+			return nil, fmt.Errorf("missing ) in filter")
+		}
+
+		return e, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *filterParser) comparison() (filterExpression, error) {
+
+	left, err := p.operand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+
+		if p.acceptOperator(op) {
+
+			right, err := p.operand()
+
+			return comparisonExpression{op: op, left: left, right: right}, err
+		}
+	}
+
+	return truthyExpression{left}, nil
+}
+
+func (p *filterParser) operand() (operand, error) {
+
+	if p.done() {
+		return operand{}, fmt.Errorf("filter ended unexpectedly")
+	}
+
+	t := p.peek()
+	p.position++
+
+	switch t.kind {
+	case literalToken:
+		return operand{value: t.value}, nil
+	case pathToken:
+
+		path := t.text
+
+		if path == "@" {
+			return operand{isPath: true}, nil
+		}
+
+		path = strings.TrimPrefix(path, "@")
+		path = strings.TrimPrefix(path, p.separator)
+
+		segments, err := parsePath(path, p.separator)
+
+		return operand{isPath: true, path: segments}, err
+	default:
+		return operand{}, fmt.Errorf("unexpected %q in filter", t.text)
+	}
+}
+
+// operand is either a literal value or a path relative to the value being filtered
+type operand struct {
+	isPath bool
+	path   []pathSegment
+	value  interface{}
+}
+
+func (o operand) resolve(current interface{}) interface{} {
+
+	if !o.isPath {
+		return o.value
+	}
+
+	return walk(o.path, current)
+}
+
+type orExpression struct {
+	left, right filterExpression
+}
+
+func (e orExpression) matches(current interface{}) bool {
+	return e.left.matches(current) || e.right.matches(current)
+}
+
+type andExpression struct {
+	left, right filterExpression
+}
+
+func (e andExpression) matches(current interface{}) bool {
+	return e.left.matches(current) && e.right.matches(current)
+}
+
+type notExpression struct {
+	e filterExpression
+}
+
+func (e notExpression) matches(current interface{}) bool {
+	return !e.e.matches(current)
+}
+
+type truthyExpression struct {
+	o operand
+}
+
+func (e truthyExpression) matches(current interface{}) bool {
+
+	v := e.o.resolve(current)
+
+	return v != nil && v != false
+}
+
+type comparisonExpression struct {
+	op          string
+	left, right operand
+}
+
+// matches compares the two operands. Numbers are compared numerically and strings lexically. Values of different types
+// are never equal and cannot be ordered. A missing value is equal to null.
+func (e comparisonExpression) matches(current interface{}) bool {
+
+	l := e.left.resolve(current)
+	r := e.right.resolve(current)
+
+	c, comparable := compareValues(l, r)
+
+	switch e.op {
+	case "==":
+		return comparable && c == 0
+	case "!=":
+		return !comparable || c != 0
+	case "<":
+		return comparable && c < 0
+	case "<=":
+		return comparable && c <= 0
+	case ">":
+		return comparable && c > 0
+	case ">=":
+		return comparable && c >= 0
+	}
+
+	return false
+}
+
+// compareValues returns -1, 0 or 1 and true if the two values are of types that can be compared
+func compareValues(l, r interface{}) (int, bool) {
+
+	if l == nil || r == nil {
+		return 0, l == nil && r == nil
+	}
+
+	if lf, isNumber := numberValue(l); isNumber {
+
+		rf, isNumber := numberValue(r)
+
+		if !isNumber {
+			return 0, false
+		}
+
+		switch {
+		case lf < rf:
+			return -1, true
+		case lf > rf:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	switch lt := l.(type) {
+	case string:
+		if rs, isString := r.(string); isString {
+			return strings.Compare(lt, rs), true
+		}
+	case bool:
+		if rb, isBool := r.(bool); isBool && lt == rb {
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+func numberValue(v interface{}) (float64, bool) {
+
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+
+	if i, err := integerValue(v); i != nil && err == nil {
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return f, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,137 @@
+package config_access_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterExpressions(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "databases.json")
+	yamlConf := loadYamlTestFile(t, "databases.yaml")
+
+	expected := map[string][]string{
+		"databases[?role=='primary'].host":                     {"db1", "db4"},
+		`$.databases[?@.role == "replica"].host`:               {"db2", "db3"},
+		"databases[?role=='primary' && port > 4000].host":      {"db1"},
+		"databases[?port >= 5433 || name == 'main'].host":      {"db1", "db2", "db3"},
+		"databases[?disabled].host":                            {"db2"},
+		"databases[?!disabled].host":                           {"db1", "db3", "db4"},
+		"databases[?disabled == null].host":                    {"db1", "db3"},
+		"databases[?tags[0] == 'reporting'].host":              {"db3"},
+		"databases[?!(role == 'replica' || port < 4000)].host": {"db1"},
+		"databases[?role == 'standby'].host":                   {},
+		"databases[?port == '5432'].host":                      {},
+		"caches[?size > 200].host":                             {"c2"},
+		"caches.*.host":                                        {"c2", "c1"},
+		"..[?port < 5000].name":                                {"legacy"},
+	}
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		for expression, hosts := range expected {
+
+			r, err := cs.Query(expression)
+			assert.NoError(t, err, expression)
+
+			sa, err := r.StringArray()
+			assert.NoError(t, err, expression)
+			assert.EqualValues(t, hosts, sa, expression)
+			assert.EqualValues(t, len(hosts), r.Len(), expression)
+		}
+	}
+}
+
+func TestFilterPathsThatLookLikeNumbers(t *testing.T) {
+
+	node := ca.ConfigNode{"items": []interface{}{
+		ca.ConfigNode{"name": "a", "inf": 1.0, "nan": true},
+		ca.ConfigNode{"name": "b", "inf": 2.0},
+	}}
+
+	// Only the JSON number grammar is a number literal, so inf and nan are paths
+	for expression, names := range map[string][]string{
+		"items[?inf].name":       {"a", "b"},
+		"items[?inf == 1].name":  {"a"},
+		"items[?nan].name":       {"a"},
+		"items[?!nan].name":      {"b"},
+		"items[?inf > 1e0].name": {"b"},
+	} {
+		r, err := ca.Query(expression, node)
+		assert.NoError(t, err, expression)
+
+		sa, err := r.StringArray()
+		assert.NoError(t, err, expression)
+		assert.EqualValues(t, names, sa, expression)
+	}
+}
+
+func TestQueryResultConversion(t *testing.T) {
+
+	node := loadJsonTestFile(t, "databases.json")
+
+	r, err := ca.Query("databases[?role == 'replica'].port", node)
+	assert.NoError(t, err)
+
+	ia, err := r.IntArray()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{5433, 5434}, ia)
+
+	fa, err := r.Float64Array()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []float64{5433, 5434}, fa)
+
+	_, err = r.StringArray()
+
+	// Errors name the concrete path of the value rather than the query expression
+	var tme ca.TypeMismatchError
+	assert.True(t, errors.As(err, &tme))
+	assert.EqualValues(t, "databases[1].port", tme.Path)
+
+	assert.EqualValues(t, "databases[1].port", r.Matches()[0].Path)
+	assert.Len(t, r.Values(), 2)
+}
+
+func TestQueryResultConversionOfNumbers(t *testing.T) {
+
+	node := ca.ConfigNode{"limits": []interface{}{json.Number("10"), 2.5, int64(3)}}
+
+	r, err := ca.Query("limits[*]", node)
+	assert.NoError(t, err)
+
+	// Every number shape accepted by the accessors is accepted
+	fa, err := r.Float64Array()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []float64{10, 2.5, 3}, fa)
+
+	_, err = r.IntArray()
+
+	var ive ca.InvalidValueError
+	assert.True(t, errors.As(err, &ive))
+	assert.EqualValues(t, "limits[1]", ive.Path)
+}
+
+func TestInvalidFilterExpressions(t *testing.T) {
+
+	node := loadJsonTestFile(t, "databases.json")
+
+	for _, expression := range []string{
+		"databases[?role == ].host",
+		"databases[?role == 'primary].host",
+		"databases[?(role == 'primary'].host",
+		"databases[?role == 'primary' 'x'].host",
+		"databases[?].host",
+	} {
+		_, err := ca.Query(expression, node)
+		assert.Error(t, err, expression)
+	}
+
+	_, err := ca.StringVal("databases[?role == 'primary'].host", node)
+	assert.Error(t, err)
+}
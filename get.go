@@ -0,0 +1,49 @@
+package config_access
+
+// Get converts the value at the supplied path to a T. T can be any type supported by Selector.Decode: scalar types
+// (including named types such as type LogLevel string), slices, arrays, maps, structs, pointers to any of
+// these and any type with a converter registered in Opts.Converters or SelectorOpts.Converters.
+//
+// If the path does not exist and Opts.OnMissing is set, OnMissing is returned. GetOrDefault is a type-safe
+// alternative to setting OnMissing.
+//
+//	port, err := config_access.Get[uint16](selector, "server.port")
+//	hosts, err := config_access.Get[map[string][]string](selector, "hosts")
+func Get[T any](s Selector, path string, o ...Opts) (T, error) {
+
+	var t T
+
+	err := s.Decode(path, &t, o...)
+
+	return t, err
+}
+
+// GetOrDefault behaves like Get, except the supplied default is returned if the path does not exist. An error is
+// still returned if the path is invalid (e.g. it cannot be parsed or it indexes into a string).
+func GetOrDefault[T any](s Selector, path string, def T, o ...Opts) (T, error) {
+
+	if !s.PathExists(path) {
+
+		var v interface{}
+
+		if err := s.Decode(path, &v); err != nil && !missing(err) {
+			var zero T
+			return zero, err
+		}
+
+		return def, nil
+	}
+
+	return Get[T](s, path, o...)
+}
+
+// GetQuiet behaves like Get, except errors are passed to the QuietSelector's error handling function and the zero value
+// of T is returned.
+func GetQuiet[T any](qs QuietSelector, path string, o ...Opts) T {
+
+	var t T
+
+	qs.Decode(path, &t, o...)
+
+	return t
+}
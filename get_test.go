@@ -0,0 +1,280 @@
+package config_access_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+type LogLevel string
+
+type Replica struct {
+	Host    string
+	Timeout *int `json:"timeout"`
+}
+
+type SearchService struct {
+	Port     uint16
+	Replicas []Replica
+}
+
+func TestGetScalars(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "simple.json")
+	yamlConf := loadYamlTestFile(t, "simple.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		s, err := ca.Get[string](cs, "simpleOne.String")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "abc", s)
+
+		l, err := ca.Get[LogLevel](cs, "simpleOne.String")
+		assert.Nil(t, err)
+		assert.EqualValues(t, LogLevel("abc"), l)
+
+		i, err := ca.Get[int64](cs, "simpleOne.Int")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 32, i)
+
+		f, err := ca.Get[float32](cs, "simpleOne.Float")
+		assert.Nil(t, err)
+		assert.InDelta(t, 32.22, f, 0.0001)
+
+		b, err := ca.Get[bool](cs, "simpleOne.Bool")
+		assert.Nil(t, err)
+		assert.True(t, b)
+
+		p, err := ca.Get[*string](cs, "simpleOne.String")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "abc", *p)
+
+		v, err := ca.Get[interface{}](cs, "simpleOne.Int")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 32, v)
+
+		_, err = ca.Get[bool](cs, "simpleOne.String")
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, ca.TypeMismatchError{Path: "simpleOne.String", Expected: "bool", Actual: "string"}, tme)
+
+		_, err = ca.Get[string](cs, "simpleOne.Missing")
+
+		var mpe ca.MissingPathError
+		assert.True(t, errors.As(err, &mpe))
+	}
+}
+
+func TestGetCollections(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "simple.json")
+	yamlConf := loadYamlTestFile(t, "simple.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		sa, err := ca.Get[[]string](cs, "simpleOne.StringArray")
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"a", "b", "c"}, sa)
+
+		ia, err := ca.Get[[]uint](cs, "simpleOne.IntArray")
+		assert.Nil(t, err)
+		assert.EqualValues(t, []uint{1, 2, 3}, ia)
+
+		sam, err := ca.Get[map[string][]string](cs, "simpleOne.StringArrayMap")
+		assert.Nil(t, err)
+		assert.EqualValues(t, map[string][]string{"key1": {"a", "b"}}, sam)
+
+		bam, err := ca.Get[map[LogLevel][]bool](cs, "simpleOne.BoolArrayMap")
+		assert.Nil(t, err)
+		assert.EqualValues(t, map[LogLevel][]bool{"key1": {true, false}}, bam)
+
+		_, err = ca.Get[[]int](cs, "simpleOne.StringArray")
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "simpleOne.StringArray[0]", tme.Path)
+
+		_, err = ca.Get[map[string]string](cs, "invalidConfig.StringMap")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "invalidConfig.StringMap.key1", tme.Path)
+
+		_, err = ca.Get[map[string]string](cs, "simpleOne.StringArray")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "map[string]string", tme.Expected)
+		assert.EqualValues(t, "array", tme.Actual)
+	}
+}
+
+func TestGetStructs(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "services.json")
+	yamlConf := loadYamlTestFile(t, "services.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		ss, err := ca.Get[SearchService](cs, "services.search")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 8003, ss.Port)
+		assert.Len(t, ss.Replicas, 2)
+		assert.EqualValues(t, "s1", ss.Replicas[0].Host)
+		assert.EqualValues(t, 1, *ss.Replicas[0].Timeout)
+		assert.Nil(t, ss.Replicas[1].Timeout)
+
+		services, err := ca.Get[map[string]*SearchService](cs, "services")
+		assert.Nil(t, err)
+		assert.Len(t, services, 3)
+		assert.EqualValues(t, 8001, services["auth"].Port)
+
+		_, err = ca.Get[[]SearchService](cs, "http.routes")
+		assert.Nil(t, err)
+
+		_, err = ca.Get[SearchService](cs, "http.timeout")
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "config_access_test.SearchService", tme.Expected)
+	}
+}
+
+func TestGetDefaults(t *testing.T) {
+
+	cs := ca.SelectorFromPathValues(map[string]interface{}{"a.b": "set"})
+
+	s, err := ca.GetOrDefault(cs, "a.c", "default")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "default", s)
+
+	s, err = ca.GetOrDefault(cs, "a.b", "default")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "set", s)
+
+	s, err = ca.GetOrDefault(cs, "x.y[0]", "default")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "default", s)
+
+	// Invalid paths are errors rather than being treated as missing
+	var pse ca.PathSyntaxError
+
+	s, err = ca.GetOrDefault(cs, "a.c[", "default")
+	assert.True(t, errors.As(err, &pse))
+	assert.EqualValues(t, "", s)
+
+	var pe ca.PathError
+
+	_, err = ca.GetOrDefault(cs, "a.b.c", "default")
+	assert.True(t, errors.As(err, &pe))
+	assert.False(t, pe.Missing())
+
+	i, err := ca.Get[int](cs, "a.c", ca.Opts{OnMissing: 8})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8, i)
+
+	var tme ca.TypeMismatchError
+
+	// An OnMissing default of the wrong type is reported rather than causing a panic
+	_, err = ca.Get[int](cs, "a.c", ca.Opts{OnMissing: "8"})
+	assert.True(t, errors.As(err, &tme))
+	assert.EqualValues(t, "int", tme.Expected)
+	assert.EqualValues(t, "string", tme.Actual)
+
+	_, err = cs.IntVal("a.c", ca.Opts{OnMissing: "8"})
+	assert.True(t, errors.As(err, &tme))
+
+	_, err = cs.StringArray("a.c", ca.Opts{OnMissing: []int{1}})
+	assert.True(t, errors.As(err, &tme))
+	assert.EqualValues(t, "[]string", tme.Expected)
+	assert.EqualValues(t, "[]int", tme.Actual)
+}
+
+func TestGetWithConverters(t *testing.T) {
+
+	type Level int
+
+	levels := ca.NewConverters()
+
+	ca.RegisterConverter(levels, func(v interface{}) (Level, error) {
+
+		s, found := v.(string)
+
+		if !found {
+			return 0, fmt.Errorf("log levels must be strings")
+		}
+
+		switch strings.ToUpper(s) {
+		case "DEBUG":
+			return 0, nil
+		case "INFO":
+			return 1, nil
+		case "ERROR":
+			return 2, nil
+		}
+
+		return 0, fmt.Errorf("unknown log level %s", s)
+	})
+
+	pv := map[string]interface{}{"log.level": "info", "log.overrides": map[string]interface{}{"db": "error"}, "log.bad": "loud"}
+
+	cs := ca.SelectorFromPathValues(pv)
+
+	l, err := ca.Get[Level](cs, "log.level", ca.Opts{Converters: levels})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, l)
+
+	// Without the converter the value is a string, so cannot be converted to an int
+	_, err = ca.Get[Level](cs, "log.level")
+	assert.NotNil(t, err)
+
+	cs = ca.SelectorFromPathValues(pv, ca.SelectorOpts{Converters: levels})
+
+	m, err := ca.Get[map[string]Level](cs, "log.overrides")
+	assert.Nil(t, err)
+	assert.EqualValues(t, map[string]Level{"db": 2}, m)
+
+	_, err = ca.Get[Level](cs, "log.bad")
+
+	var ive ca.InvalidValueError
+	assert.True(t, errors.As(err, &ive))
+	assert.EqualValues(t, "log.bad", ive.Path)
+	assert.EqualValues(t, "unknown log level loud", ive.Err.Error())
+}
+
+func TestGetQuiet(t *testing.T) {
+
+	var errs []string
+
+	qs := ca.QuietSelectorFromPathValues(map[string]interface{}{"a.b": []interface{}{"x", "y"}}, func(path string, err error) {
+		errs = append(errs, path)
+	})
+
+	assert.EqualValues(t, []string{"x", "y"}, ca.GetQuiet[[]string](qs, "a.b"))
+	assert.Empty(t, errs)
+
+	assert.Nil(t, ca.GetQuiet[[]bool](qs, "a.b"))
+	assert.EqualValues(t, 0, ca.GetQuiet[int](qs, "a.c"))
+	assert.EqualValues(t, []string{"a.b", "a.c"}, errs)
+}
+
+func TestDecodeInvalidTarget(t *testing.T) {
+
+	cs := ca.SelectorFromPathValues(map[string]interface{}{"a": "b"})
+
+	var s string
+
+	assert.NotNil(t, cs.Decode("a", s))
+	assert.NotNil(t, cs.Decode("a", nil))
+	assert.Nil(t, cs.Decode("a", &s))
+	assert.EqualValues(t, "b", s)
+
+	assert.True(t, errors.Is(ca.Decode("a", nil, &s), ca.ErrNilConfig))
+}
@@ -2,10 +2,12 @@ module github.com/graniticio/config-access
 
 go 1.20
 
-require github.com/stretchr/testify v1.6.0
+require (
+	github.com/stretchr/testify v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -5,155 +5,702 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-func SetField(fieldName string, path string, target interface{}, config ConfigNode) error {
+// SetField sets the named field of the supplied target, which must be a pointer to a struct, to the value at the
+// supplied path. Fields of string, bool, numeric, slice and map types, of the types with built-in conversions (e.g.
+// time.Duration), of types that implement encoding.TextUnmarshaler or json.Unmarshaler and of types with a converter
+// registered in Opts.Converters are supported. If the value cannot be converted to the type of the field, the field is
+// unchanged and a PopulateError is returned.
+func SetField(fieldName string, path string, target interface{}, config ConfigNode, o ...Opts) error {
 
 	if !PathExists(path, config) {
-		return MissingPathError{message: "No value found at " + path}
-	}
-
-	targetReflect := reflect.ValueOf(target).Elem()
-	targetField := targetReflect.FieldByName(fieldName)
-
-	k := targetField.Type().Kind()
-
-	switch k {
-	case reflect.String:
-		s, _ := StringVal(path, config)
-		targetField.SetString(s)
-	case reflect.Bool:
-		b, _ := BoolVal(path, config)
-		targetField.SetBool(b)
-	case reflect.Int:
-		i, _ := IntVal(path, config)
-		targetField.SetInt(int64(i))
-	case reflect.Float64:
-		f, _ := Float64Val(path, config)
-		targetField.SetFloat(f)
-	case reflect.Map:
-
-		if v, err := ObjectVal(path, config, false); err == nil {
-			if err = populateMapField(targetField, v); err != nil {
-				return err
-			}
-		} else {
-			return err
+		return MissingPathError{Path: path}
+	}
+
+	targetReflect := reflect.ValueOf(target)
+
+	if targetReflect.Kind() != reflect.Pointer || targetReflect.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to a struct, not %T", target)
+	}
+
+	targetField := targetReflect.Elem().FieldByName(fieldName)
+
+	if !targetField.IsValid() {
+		return fmt.Errorf("%T has no field %s", target, fieldName)
+	} else if !targetField.CanSet() {
+		return fmt.Errorf("field %s of %T cannot be set", fieldName, target)
+	}
+
+	c := defaultAccessor.conversion(config, options(o))
+	c.field = fieldName
+
+	ft := targetField.Type()
+
+	if !settable(ft) && !c.hasConverter(ft) && !builtinType(ft) {
+		return UnsupportedFieldError{Path: path, Field: fieldName, Type: ft.String()}
+	}
+
+	v := Value(path, config)
+
+	var err error
+
+	if ft.Kind() == reflect.Map && !c.hasConverter(ft) {
+
+		if !c.keyType(ft.Key()) {
+			return UnsupportedFieldError{Path: path, Field: fieldName, Type: ft.String()}
+		}
+
+		err = c.emptyArrayMembers(path, v)
+	}
+
+	if err == nil {
+
+		// Converted into a copy so that the field is unchanged if the conversion fails
+		cv := reflect.New(ft).Elem()
+		cv.Set(targetField)
+
+		if err = c.set(path, v, cv); err == nil {
+			targetField.Set(cv)
 		}
-	case reflect.Slice:
-		populateSlice(targetField, path, config)
+	}
 
-	default:
-		m := fmt.Sprintf("Unable to use value at path %s as target field %s is not a suppported type (%s)", path, fieldName, k)
-		return errors.New(m)
+	if err != nil {
+		return PopulateError{Fields: c.fieldErrors(path, err)}
 	}
 
 	return nil
 }
 
-// PopulateFromRoot sets the fields on the supplied target object using the whole supplied config document.
-// This is achieved using Go's json.Marshal to convert the data
-// back into text JSON and then json.Unmarshal to unmarshal back into the target.
-func PopulateFromRoot(target interface{}, config ConfigNode) error {
+// emptyArrayMembers returns an error for each member of the object at the supplied path that is an empty array, as
+// SetField does not accept empty arrays as the values of map fields
+func (c conversion) emptyArrayMembers(path string, v interface{}) error {
+
+	o, found := object(v)
+
+	if !found {
+		return nil
+	}
+
+	keys := make([]string, 0, len(o))
+
+	for k, m := range o {
+		if a, found := m.([]interface{}); found && len(a) == 0 {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, k := range keys {
+		errs = append(errs, InvalidValueError{Path: c.memberPath(path, k), Expected: "map value", Err: errors.New("cannot use an empty array as a value in a Map")})
+	}
+
+	return errors.Join(errs...)
+}
+
+// settable returns true if SetField supports fields of the supplied kind without a converter
+func settable(t reflect.Type) bool {
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float64, reflect.Float32, reflect.Slice, reflect.Array, reflect.Map,
+		reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return true
+	}
+
+	return false
+}
 
-	wrapper := make(ConfigNode)
+// PopulateFromRoot sets the fields of the supplied target using the whole supplied config document. See Populate.
+func PopulateFromRoot(target interface{}, config ConfigNode, o ...Opts) error {
 
-	wrapper["root"] = config
+	if config == nil {
+		return ErrNilConfig
+	}
 
-	return Populate("root", target, wrapper)
+	return defaultAccessor.conversion(config, options(o)).populate("", config, target)
 }
 
-// Populate sets the fields on the supplied target object using the data
-// at the supplied path. This is achieved using Go's json.Marshal to convert the data
-// back into text JSON and then json.Unmarshal to unmarshal back into the target.
-func Populate(path string, target interface{}, config ConfigNode) error {
+// Populate sets the fields of the supplied target, which must be a pointer to a struct, using the object at the
+// supplied path. Each exported field is populated from the member of the object named by its config tag, its json tag
+// or, if it has neither, the member whose key matches the field's name (ignoring case). A config tag can contain a
+// path relative to the object (e.g. `config:"pool.maxSize"`) or, if it starts with $ and the separator, a path from the
+// root of the config (e.g. `config:"$.logging.level"`). Fields tagged `config:"-"` are ignored.
+//
+// Nested structs, pointers, slices and maps are populated recursively and values are converted as described in Get.
+// Fields with no value (or a null value) in the config are set to the value of their default tag if they have one
+// (e.g. `config:"db.port" default:"5432"`), are reported as missing if they are tagged `required:"true"` and otherwise
+// keep their existing values. Every field is populated, even if some of them fail, so that the returned error reports
+// the path of every value that is missing or could not be used.
+//
+// If Opts.Interpolate or Opts.Resolvers is set, references in strings are expanded and resolved as described in
+// SelectorOpts. If Opts.Strict is set, every key in the object (and in the objects used to populate nested structs)
+// that does not map to a field is reported as an UnknownKeyError, so that misspelt keys are not silently ignored.
+func Populate(path string, target interface{}, config ConfigNode, o ...Opts) error {
 	if !PathExists(path, config) {
-		return MissingPathError{message: "No value found at " + path}
+		return MissingPathError{Path: path}
 	}
 
-	//Already check if path exists
-	object, _ := ObjectVal(path, config, false)
+	object, err := ObjectVal(path, config, false)
+
+	if err != nil {
+		return err
+	}
+
+	return defaultAccessor.conversion(config, options(o)).populate(path, object, target)
+}
+
+func (c conversion) populate(path string, object ConfigNode, target interface{}) error {
+
+	tv := reflect.ValueOf(target)
+
+	if tv.Kind() != reflect.Pointer || tv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, not %T", target)
+	}
 
-	if data, err := json.Marshal(object); err != nil {
-		m := fmt.Sprintf("%T cannot be marshalled to JSON", object)
-		return errors.New(m)
-	} else if json.Unmarshal(data, target); err != nil {
-		return fmt.Errorf("%T cannot be populated with %v to JSON", object, data)
+	if err := c.set(path, object, tv.Elem()); err != nil {
+		return PopulateError{Fields: c.fieldErrors(path, err)}
 	}
 
 	return nil
+}
+
+// set converts the supplied value and assigns it to the target. Structs (and structs that pointers already point to)
+// are populated in place, so that fields without a value in the config keep their existing values.
+func (c conversion) set(path string, v interface{}, target reflect.Value) error {
+
+	if o, found := object(v); found && c.populatesInPlace(target.Type()) {
+
+		if target.Kind() == reflect.Pointer {
 
+			if target.IsNil() {
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+
+			target = target.Elem()
+		}
+
+		return c.populateStruct(path, o, target)
+	}
+
+	cv, err := c.convert(path, v, target.Type())
+
+	if err != nil {
+		return err
+	}
+
+	target.Set(cv)
+
+	return nil
 }
 
-func populateSlice(targetField reflect.Value, path string, config ConfigNode) {
+// populatesInPlace returns true if the supplied type is a struct, or pointer to a struct, that is populated field by
+// field rather than converted by a registered converter or built-in conversion
+func (c conversion) populatesInPlace(t reflect.Type) bool {
 
-	v := Value(path, config)
+	if c.hasConverter(t) {
+		return false
+	}
+
+	if t.Kind() == reflect.Pointer {
+
+		if t = t.Elem(); c.hasConverter(t) {
+			return false
+		}
+	}
+
+	return t.Kind() == reflect.Struct && !builtinType(t)
+}
+
+// populateStruct populates the fields of the target from the supplied object, returning a PopulateError describing
+// every field that could not be populated
+func (c conversion) populateStruct(path string, o ConfigNode, target reflect.Value) error {
+
+	var failed []FieldError
+
+	fields := structFields(target.Type())
+
+	for _, b := range fields {
+
+		fc := c
+		fc.field = qualifyField(c.field, b.Name)
 
-	data, _ := json.Marshal(v)
+		fp, v, found, err := fc.fieldValue(path, o, b)
 
-	vt := targetField.Type()
-	nt := reflect.New(vt)
+		if err == nil {
+			err = b.invalid
+		}
+
+		if err != nil {
+			failed = append(failed, fc.fieldErrors(fp, err)...)
+			continue
+		}
+
+		// Embedded pointers are only allocated if the field will be set
+		field, reachable := fieldByIndex(target, b.Index, found || b.hasDefault)
+
+		switch {
+		case found:
+			err = fc.set(fp, v, field)
+		case b.hasDefault:
+			err = fc.setDefault(fp, b, field)
+		case b.required:
+			err = MissingPathError{Path: fp}
+		case reachable && field.Kind() == reflect.Struct && fc.populatesInPlace(field.Type()):
+			// Defaults and required fields of a nested struct apply even if it has no value in the config
+			err = fc.populateStruct(fp, nil, field)
+		}
+
+		if err != nil {
+			failed = append(failed, fc.fieldErrors(fp, err)...)
+		}
+	}
 
-	jTarget := nt.Interface()
-	json.Unmarshal(data, &jTarget)
+	if c.strict && o != nil {
+		for _, err := range c.unknownKeys(path, o, c.usedKeys(path, o, fields), target.Type()) {
+			failed = append(failed, c.fieldErrors(path, err)...)
+		}
+	}
 
-	vr := reflect.ValueOf(jTarget)
-	targetField.Set(vr.Elem())
+	if len(failed) > 0 {
+		return PopulateError{Fields: failed}
+	}
 
+	return nil
 }
 
-func populateMapField(targetField reflect.Value, contents map[string]interface{}) error {
-	var err error
+// keyTree records the keys of an object that map to the fields of a struct. A key that maps to nil is used in its
+// entirety, otherwise only the keys in its own keyTree are used (e.g. by a field tagged config:"http.timeouts.read").
+type keyTree map[string]keyTree
 
-	m := reflect.MakeMap(targetField.Type())
-	targetField.Set(m)
+// usedKeys returns the keys of the supplied object, which is at the supplied path, that map to the supplied fields.
+// Paths from the root of the config are included if they refer to a value inside the object.
+func (c conversion) usedKeys(path string, o ConfigNode, fields []fieldBinding) keyTree {
 
-	for k, v := range contents {
+	used := make(keyTree)
 
-		keyVal := reflect.ValueOf(k)
-		vVal := reflect.ValueOf(v)
+	for _, b := range fields {
 
-		if vVal.Kind() == reflect.Slice {
-			vVal, err = arrayVal(vVal)
+		if !b.path {
 
-			if err != nil {
-				return err
+			if key, found := memberKey(o, b.name); found {
+				used[key] = nil
 			}
+
+			continue
+		}
+
+		segments, found := c.relativeSegments(path, b.name)
+
+		if !found || len(segments) == 0 || segments[0].kind != keySegment {
+			continue
+		}
+
+		node := used
+
+		for i, seg := range segments {
+
+			sub, found := node[seg.key]
+
+			if found && sub == nil {
+				// Already used in its entirety
+				break
+			}
+
+			if i == len(segments)-1 || segments[i+1].kind != keySegment {
+				// The rest of the path is within the value of this key (e.g. an element of an array)
+				node[seg.key] = nil
+				break
+			}
+
+			if !found {
+				sub = make(keyTree)
+				node[seg.key] = sub
+			}
+
+			node = sub
+		}
+	}
+
+	return used
+}
+
+// relativeSegments parses the path in a field's config tag, returning the segments of the path relative to the object
+// at the supplied path. A path from the root of the config is only found if it is inside the object.
+func (c conversion) relativeSegments(path, name string) ([]pathSegment, bool) {
+
+	absolute, isAbsolute := strings.CutPrefix(name, "$"+c.separator)
+
+	if !isAbsolute {
+		segments, err := parsePath(name, c.separator)
+		return segments, err == nil
+	}
+
+	segments, err := parsePath(absolute, c.separator)
+
+	if err != nil {
+		return nil, false
+	}
+
+	var prefix []pathSegment
+
+	if path != "" {
+		if prefix, err = parsePath(path, c.separator); err != nil {
+			return nil, false
+		}
+	}
+
+	if len(prefix) > len(segments) {
+		return nil, false
+	}
+
+	for i, seg := range prefix {
+		if !sameSegment(seg, segments[i]) {
+			return nil, false
+		}
+	}
+
+	return segments[len(prefix):], true
+}
+
+// sameSegment returns true if two key or index segments refer to the same member or element
+func sameSegment(a, b pathSegment) bool {
+
+	switch {
+	case a.kind == keySegment && b.kind == keySegment:
+		return a.key == b.key
+	case a.kind == indexSegment && b.kind == indexSegment:
+		return a.index == b.index
+	case a.kind == indexSegment && b.kind == keySegment:
+		return b.key == strconv.Itoa(a.index)
+	case a.kind == keySegment && b.kind == indexSegment:
+		return a.key == strconv.Itoa(b.index)
+	}
+
+	return false
+}
+
+// unknownKeys returns an UnknownKeyError for every key in the supplied object, at any depth, that is not used
+func (c conversion) unknownKeys(path string, o ConfigNode, used keyTree, t reflect.Type) []error {
+
+	keys := make([]string, 0, len(o))
+
+	for k := range o {
+		keys = append(keys, k)
+	}
+
+	// Sorted so that errors are reported in a consistent order
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, k := range keys {
+
+		sub, found := used[k]
+		kp := c.memberPath(path, k)
+
+		if !found {
+			errs = append(errs, UnknownKeyError{Path: kp, Type: t.String()})
+		} else if mo, isObject := object(o[k]); isObject && sub != nil {
+			errs = append(errs, c.unknownKeys(kp, mo, sub, t)...)
+		}
+	}
+
+	return errs
+}
+
+// qualifyField returns the name of a field of the struct in the supplied field (e.g. Database.Port)
+func qualifyField(parent, name string) string {
+
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+// fieldErrors converts an error that occurred while populating the current field from the value at the supplied path
+// into FieldErrors, flattening the errors of nested structs and the elements of arrays and objects
+func (c conversion) fieldErrors(path string, err error) []FieldError {
+
+	switch e := err.(type) {
+	case PopulateError:
+		return e.Fields
+	case interface{ Unwrap() []error }:
+		var fe []FieldError
+
+		for _, u := range e.Unwrap() {
+			fe = append(fe, c.fieldErrors(path, u)...)
+		}
+
+		return fe
+	}
+
+	if p, found := errorPath(err); found {
+		path = p
+	}
+
+	return []FieldError{{Path: path, Field: c.field, Err: err}}
+}
+
+// setDefault sets a field to the value in its default tag. Defaults are converted in the same way as values in config,
+// except that strings are always coerced to numbers and bools. Defaults of slice, array, map and struct fields that
+// start with [ or { are parsed as JSON arrays or objects.
+func (c conversion) setDefault(path string, b fieldBinding, field reflect.Value) error {
+
+	var v interface{} = b.def
+
+	if trimmed := strings.TrimSpace(b.def); composite(field.Type()) && (strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{")) {
+
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return fmt.Errorf("default %s is not valid JSON: %w", b.def, err)
 		}
+	}
 
-		m.SetMapIndex(keyVal, vVal)
+	c.coerce = true
 
+	if err := c.set(path, v, field); err != nil {
+		return fmt.Errorf("default %q cannot be used: %w", b.def, err)
 	}
 
 	return nil
 }
 
-func arrayVal(a reflect.Value) (reflect.Value, error) {
+// composite returns true if the supplied type, or the type it points to, is a slice, array, map or struct
+func composite(t reflect.Type) bool {
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		return true
+	}
+
+	return false
+}
+
+// fieldBinding is an exported field of a struct and the location in config of the value that populates it
+type fieldBinding struct {
+	reflect.StructField
+	// The key of the member of the object that populates the field or, if path is set, the path to the value
+	name string
+	// Set if name is a path from a config tag
+	path bool
+	// Set if name is from a config or json tag
+	tagged bool
+	// The value of the field's default tag, used if there is no value in the config
+	def        string
+	hasDefault bool
+	// Set if the field's required tag is true
+	required bool
+	// Set if the field's tags cannot be used, in which case the field is not populated
+	invalid error
+}
 
-	v := a.Interface().([]interface{})
-	l := len(v)
+// structFields returns the fields of the supplied struct type that can be populated from config. Fields of embedded
+// structs (and pointers to structs) without a tag are included and their Index is the sequence of indexes required by
+// fieldByIndex. As with encoding/json, a field hidden by another field bound to the same key is excluded.
+func structFields(t reflect.Type) []fieldBinding {
+	return dominantFields(embeddedFields(t, map[reflect.Type]bool{t: true}))
+}
 
-	if l == 0 {
+// dominantFields removes the fields that share a key (ignoring case) with another field following the rules of
+// encoding/json: the least deeply embedded field is used, a tagged field is preferred to untagged fields at the same
+// depth and, if that does not leave a single field, none of the fields are used
+func dominantFields(fields []fieldBinding) []fieldBinding {
 
-		return reflect.Zero(reflect.TypeOf(v)), errors.New("cannot use an empty array as a value in a Map")
+	byKey := make(map[string][]int)
 
+	for i, b := range fields {
+		k := strings.ToLower(b.name)
+		byKey[k] = append(byKey[k], i)
 	}
 
-	var s reflect.Value
+	keep := make([]bool, len(fields))
+
+	for _, indexes := range byKey {
+
+		depth := len(fields[indexes[0]].Index)
+
+		for _, i := range indexes {
+			if len(fields[i].Index) < depth {
+				depth = len(fields[i].Index)
+			}
+		}
+
+		var shallowest, tagged []int
+
+		for _, i := range indexes {
+
+			if len(fields[i].Index) != depth {
+				continue
+			}
+
+			shallowest = append(shallowest, i)
+
+			if fields[i].tagged {
+				tagged = append(tagged, i)
+			}
+		}
+
+		if len(shallowest) == 1 {
+			keep[shallowest[0]] = true
+		} else if len(tagged) == 1 {
+			keep[tagged[0]] = true
+		}
+	}
+
+	var dominant []fieldBinding
+
+	for i, b := range fields {
+		if keep[i] {
+			dominant = append(dominant, b)
+		}
+	}
+
+	return dominant
+}
+
+// embeddedFields returns the fields of the supplied struct type, skipping embedded structs whose types have already
+// been visited so that recursively embedded pointers terminate
+func embeddedFields(t reflect.Type, visited map[reflect.Type]bool) []fieldBinding {
+
+	var fields []fieldBinding
+
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+
+		tag, hasTag := f.Tag.Lookup("config")
+		name, tagged := fieldKey(f)
+
+		if tag == "-" || (!hasTag && name == "-") {
+			continue
+		}
+
+		et, pointer := f.Type, f.Type.Kind() == reflect.Pointer
+
+		if pointer {
+			et = et.Elem()
+		}
+
+		if f.Anonymous && !tagged && !hasTag && et.Kind() == reflect.Struct {
+
+			// A pointer to an unexported struct type cannot be allocated, so its fields cannot be populated
+			if (pointer && !f.IsExported()) || visited[et] {
+				continue
+			}
+
+			visited[et] = true
+
+			// Members of an embedded struct are promoted to the enclosing object, even if the embedded struct's type is
+			// unexported (as with encoding/json)
+			for _, ef := range embeddedFields(et, visited) {
+				ef.Index = append([]int{i}, ef.Index...)
+				fields = append(fields, ef)
+			}
+
+			delete(visited, et)
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		b := fieldBinding{StructField: f, name: name, tagged: tagged}
+
+		if hasTag && tag != "" {
+			b.name, b.path, b.tagged = tag, true, true
+		}
+
+		b.def, b.hasDefault = f.Tag.Lookup("default")
+		if r, found := f.Tag.Lookup("required"); found {
+
+			var err error
+
+			if b.required, err = strconv.ParseBool(r); err != nil {
+				b.invalid = fmt.Errorf("required tag %q is not true or false", r)
+			}
+		}
+
+		fields = append(fields, b)
+	}
+
+	return fields
+}
+
+// fieldByIndex returns the field of the supplied struct at the supplied sequence of indexes. Nil pointers to embedded
+// structs on the way to the field are allocated if alloc is true, otherwise false is returned if one is found.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+
+	for i, x := range index {
+
+		if i > 0 && v.Kind() == reflect.Pointer {
+
+			if v.IsNil() {
+
+				if !alloc {
+					return reflect.Value{}, false
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// fieldValue finds the value in config for the supplied field of a struct being populated from the object at the
+// supplied path. It returns the path of the value and whether a (non-null) value was found.
+func (c conversion) fieldValue(path string, o ConfigNode, b fieldBinding) (string, interface{}, bool, error) {
+
+	if !b.path {
+
+		key, found := memberKey(o, b.name)
+
+		if !found {
+			return c.memberPath(path, b.name), nil, false, nil
+		}
+
+		return c.memberPath(path, key), o[key], o[key] != nil, nil
+	}
+
+	node, relative, fp := o, b.name, b.name
+
+	if rest, found := strings.CutPrefix(b.name, "$"+c.separator); found {
+		node, relative, fp = c.root, rest, rest
+	} else if path != "" {
+		fp = path + c.separator + b.name
+	}
 
-	switch t := v[0].(type) {
-	case string:
-		s = reflect.MakeSlice(reflect.TypeOf([]string{}), 0, 0)
-	default:
-		m := fmt.Sprintf("Cannot use an array of %T as a value in a Map.", t)
-		return reflect.Zero(reflect.TypeOf(v[0])), errors.New(m)
+	if node == nil {
+		return fp, nil, false, nil
 	}
 
-	for _, elem := range v {
+	v, err := accessor{pathSeparator: c.separator}.lookup(relative, node)
 
-		s = reflect.Append(s, reflect.ValueOf(elem))
+	var pe PathError
 
+	if errors.As(err, &pe) && pe.Missing() {
+		return fp, nil, false, nil
+	} else if err != nil {
+		return fp, nil, false, err
 	}
 
-	return s, nil
+	return fp, v, v != nil, nil
 }
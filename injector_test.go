@@ -1,9 +1,16 @@
 package config_access_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
 	ca "github.com/graniticio/config-access"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 type SimpleConfig struct {
@@ -87,8 +94,59 @@ func TestPopulateOutOfBoundsNumbers(t *testing.T) {
 
 		err := ca.Populate("simpleOne", &sc, node)
 
-		assert.Nil(t, err)
+		// A number with a fractional part cannot populate an int, but the other fields are still populated
+		var ive ca.InvalidValueError
+
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "simpleOne.Int", ive.Path)
+		assert.EqualValues(t, 32, sc.Float)
+	}
+}
+
+func TestPopulateArraysAndKeyedMaps(t *testing.T) {
+
+	var c struct {
+		Arr    [3]int
+		Ports  map[int]string
+		Levels map[Severity]bool
+		Ratio  float32
 	}
+
+	node := ca.ConfigNode{
+		"arr":    []interface{}{1.0, 2.0, 3.0},
+		"ports":  ca.ConfigNode{"80": "http", "443": "https"},
+		"levels": ca.ConfigNode{"warn": true},
+		"ratio":  0.5,
+	}
+
+	assert.Nil(t, ca.PopulateFromRoot(&c, node))
+	assert.EqualValues(t, [3]int{1, 2, 3}, c.Arr)
+	assert.EqualValues(t, map[int]string{80: "http", 443: "https"}, c.Ports)
+	assert.EqualValues(t, map[Severity]bool{2: true}, c.Levels)
+	assert.EqualValues(t, 0.5, c.Ratio)
+
+	node = ca.ConfigNode{
+		"arr":   []interface{}{1.0, 2.0},
+		"ports": ca.ConfigNode{"http": "http"},
+		"ratio": 1e300,
+	}
+
+	err := ca.PopulateFromRoot(&c, node)
+
+	// Arrays must have the same length as the Go array
+	var ive ca.InvalidValueError
+	assert.True(t, errors.As(err, &ive))
+	assert.EqualValues(t, "arr", ive.Path)
+	assert.EqualValues(t, "[3]int", ive.Expected)
+
+	// Keys are converted to the map's key type
+	assert.Contains(t, err.Error(), `value at ports.http cannot be converted to an int: "http" is not a number`)
+
+	// Numbers outside the range of a float32 are rejected rather than becoming infinite
+	var ore ca.OutOfRangeError
+	assert.True(t, errors.As(err, &ore))
+	assert.EqualValues(t, "ratio", ore.Path)
+	assert.EqualValues(t, "float32", ore.Expected)
 }
 
 func TestSetField(t *testing.T) {
@@ -184,7 +242,15 @@ func TestPopulateInvalid(t *testing.T) {
 		var sc SimpleConfig
 
 		err := ca.Populate("invalidConfig", &sc, node)
-		assert.NoError(t, err)
+		assert.Error(t, err)
+
+		// Every invalid value is reported
+		assert.Contains(t, err.Error(), "invalidConfig.String")
+		assert.Contains(t, err.Error(), "invalidConfig.IntArray")
+		assert.Contains(t, err.Error(), "invalidConfig.StringMap.key1")
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
 	}
 }
 
@@ -202,3 +268,669 @@ func TestPopulateWithUnmarshalableContent(t *testing.T) {
 		assert.Error(t, err)
 	}
 }
+
+func TestSetFieldInvalidTargets(t *testing.T) {
+
+	node := loadJsonTestFile(t, "simple.json")
+
+	var sc SimpleConfig
+
+	assert.Error(t, ca.SetField("String", "simpleOne.String", sc, node))
+	assert.Error(t, ca.SetField("NoSuchField", "simpleOne.String", &sc, node))
+
+	s := "x"
+	assert.Error(t, ca.SetField("String", "simpleOne.String", &s, node))
+
+	// Map values of the wrong type
+	assert.Error(t, ca.SetField("StringMap", "invalidConfig.StringMap", &sc, node))
+
+	mixed := ca.ConfigNode{"m": ca.ConfigNode{"k": []interface{}{"a", 1.0}}}
+	assert.Error(t, ca.SetField("StringArrayMap", "m", &sc, mixed))
+
+	assert.Error(t, ca.SetField("String", "simpleOne.String.x", &sc, node))
+}
+
+func TestSetFieldReportsConversionFailures(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "simple.json")
+	yamlConf := loadYamlTestFile(t, "simple.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		sc := SimpleConfig{String: "unchanged", Bool: true}
+
+		err := ca.SetField("String", "simpleOne.Bool", &sc, node)
+
+		var pe ca.PopulateError
+		assert.True(t, errors.As(err, &pe))
+		assert.Len(t, pe.Fields, 1)
+		assert.Equal(t, "String", pe.Fields[0].Field)
+		assert.Equal(t, "simpleOne.Bool", pe.Fields[0].Path)
+		assert.EqualValues(t, "unchanged", sc.String)
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(ca.SetField("Bool", "simpleOne.String", &sc, node), &tme))
+		assert.True(t, sc.Bool)
+
+		assert.Error(t, ca.SetField("IntArray", "simpleOne.String", &sc, node))
+	}
+}
+
+func TestSetFieldMaps(t *testing.T) {
+
+	var mc struct {
+		Counts  map[string]int
+		Names   map[*string]string
+		Strings map[string]string
+	}
+
+	node := ca.ConfigNode{
+		"counts":  map[string]interface{}{"a": 1.0, "b": 2.0},
+		"partial": map[string]interface{}{"a": "x", "b": 2.0},
+	}
+
+	assert.Nil(t, ca.SetField("Counts", "counts", &mc, node))
+	assert.EqualValues(t, map[string]int{"a": 1, "b": 2}, mc.Counts)
+
+	// A failed conversion leaves the existing map unchanged
+	mc.Strings = map[string]string{"keep": "me"}
+
+	var tme ca.TypeMismatchError
+	assert.True(t, errors.As(ca.SetField("Strings", "partial", &mc, node), &tme))
+	assert.EqualValues(t, "partial.b", tme.Path)
+	assert.EqualValues(t, map[string]string{"keep": "me"}, mc.Strings)
+
+	var ufe ca.UnsupportedFieldError
+	assert.True(t, errors.As(ca.SetField("Names", "counts", &mc, node), &ufe))
+	assert.EqualValues(t, "Names", ufe.Field)
+	assert.Nil(t, mc.Names)
+}
+
+type Backend struct {
+	Host   string `config:"host"`
+	Weight int    `config:"weight"`
+}
+
+type Limit struct {
+	Rate float64
+}
+
+type Cache struct {
+	TTL time.Duration `config:"ttl"`
+}
+
+type ServiceConfig struct {
+	Name         string
+	Listen       ca.HostPort      `config:"http.listen"`
+	ReadTimeout  time.Duration    `config:"http.timeouts.read"`
+	WriteTimeout time.Duration    `config:"http.timeouts.write"`
+	PoolSize     int              `config:"pool.maxSize"`
+	LogLevel     string           `config:"$.logging.level"`
+	Backends     []Backend        `config:"backends"`
+	Limits       map[string]Limit `config:"limits"`
+	Cache        *Cache
+	Ignored      string `config:"-"`
+	Unset        string `config:"no.such.path"`
+}
+
+func TestPopulateWithConfigTags(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "tagged.json")
+	yamlConf := loadYamlTestFile(t, "tagged.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		sc := ServiceConfig{Ignored: "kept", Unset: "default"}
+
+		err := ca.Populate("service", &sc, node)
+		assert.Nil(t, err)
+
+		assert.EqualValues(t, "orders", sc.Name)
+		assert.EqualValues(t, ca.HostPort{Host: "0.0.0.0", Port: 8080}, sc.Listen)
+		assert.EqualValues(t, 5*time.Second, sc.ReadTimeout)
+		assert.EqualValues(t, 10*time.Second, sc.WriteTimeout)
+		assert.EqualValues(t, 20, sc.PoolSize)
+		assert.EqualValues(t, "DEBUG", sc.LogLevel)
+		assert.EqualValues(t, []Backend{{Host: "a.internal", Weight: 1}, {Host: "b.internal", Weight: 3}}, sc.Backends)
+		assert.EqualValues(t, map[string]Limit{"search": {Rate: 10}, "orders": {Rate: 50}}, sc.Limits)
+		assert.EqualValues(t, time.Minute, sc.Cache.TTL)
+		assert.EqualValues(t, "kept", sc.Ignored)
+		assert.EqualValues(t, "default", sc.Unset)
+	}
+}
+
+// fieldPaths returns the path of each field listed in the PopulateError in the supplied error, keyed by field name
+func fieldPaths(t *testing.T, err error) map[string]string {
+
+	var pe ca.PopulateError
+	assert.True(t, errors.As(err, &pe))
+
+	fields := make(map[string]string)
+
+	for _, fe := range pe.Fields {
+		fields[fe.Field] = fe.Path
+		assert.Error(t, fe.Err)
+	}
+
+	return fields
+}
+
+func TestPopulateReportsEveryFailure(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "tagged.json")
+	yamlConf := loadYamlTestFile(t, "tagged.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var sc ServiceConfig
+
+		err := ca.Populate("broken", &sc, node)
+		assert.Error(t, err)
+
+		for _, path := range []string{"broken.name", "broken.http.listen", "broken.http.timeouts.read", "broken.pool.maxSize", "broken.backends[0].weight"} {
+			assert.Contains(t, err.Error(), path)
+		}
+
+		assert.Equal(t, map[string]string{
+			"Name":               "broken.name",
+			"Listen":             "broken.http.listen",
+			"ReadTimeout":        "broken.http.timeouts.read",
+			"PoolSize":           "broken.pool.maxSize",
+			"Backends[0].Weight": "broken.backends[0].weight",
+		}, fieldPaths(t, err))
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+
+		// Fields without problems are still populated
+		assert.EqualValues(t, "DEBUG", sc.LogLevel)
+		assert.Nil(t, sc.Backends)
+	}
+}
+
+func TestPopulateInPlace(t *testing.T) {
+
+	node := loadJsonTestFile(t, "tagged.json")
+
+	type Outer struct {
+		Pool struct {
+			MaxSize int
+			MinSize int
+		}
+		Cache *Cache
+	}
+
+	o := Outer{Cache: &Cache{TTL: time.Second}}
+	o.Pool.MinSize = 2
+
+	existing := o.Cache
+
+	assert.Nil(t, ca.Populate("service", &o, node))
+	assert.EqualValues(t, 20, o.Pool.MaxSize)
+	assert.EqualValues(t, 2, o.Pool.MinSize)
+	assert.Same(t, existing, o.Cache)
+	assert.EqualValues(t, time.Minute, o.Cache.TTL)
+
+	var m map[string]interface{}
+
+	assert.Nil(t, ca.Populate("service.pool", &m, node))
+	assert.EqualValues(t, 20, m["maxSize"])
+
+	assert.Error(t, ca.Populate("service", o, node))
+}
+
+type endpoint struct {
+	Host string
+	Port int
+}
+
+type EmbeddingServer struct {
+	endpoint
+	Cache
+	Name string
+}
+
+func TestPopulateEmbeddedStructs(t *testing.T) {
+
+	node := ca.ConfigNode{"server": map[string]interface{}{"host": "localhost", "port": 8080.0, "ttl": "1m", "name": "api"}}
+
+	var es EmbeddingServer
+
+	assert.Nil(t, ca.Populate("server", &es, node, ca.Opts{Strict: true}))
+
+	// The exported fields of an unexported embedded struct are promoted, as with encoding/json
+	assert.EqualValues(t, "localhost", es.Host)
+	assert.EqualValues(t, 8080, es.Port)
+	assert.EqualValues(t, time.Minute, es.TTL)
+	assert.EqualValues(t, "api", es.Name)
+}
+
+type Tuning struct {
+	Retries int `default:"3"`
+}
+
+type EmbeddingPointers struct {
+	*endpoint
+	*Cache
+	*Tuning
+	Name string
+}
+
+func TestPopulateEmbeddedPointers(t *testing.T) {
+
+	var ep EmbeddingPointers
+
+	assert.Nil(t, ca.PopulateFromRoot(&ep, ca.ConfigNode{"ttl": "1m", "name": "api"}, ca.Opts{Strict: true}))
+
+	// Pointers to exported embedded structs are allocated if one of their fields has a value or a default
+	assert.NotNil(t, ep.Cache)
+	assert.EqualValues(t, time.Minute, ep.TTL)
+	assert.NotNil(t, ep.Tuning)
+	assert.EqualValues(t, 3, ep.Retries)
+	assert.EqualValues(t, "api", ep.Name)
+
+	// Pointers to unexported embedded structs cannot be allocated
+	assert.Nil(t, ep.endpoint)
+
+	ep = EmbeddingPointers{}
+
+	assert.Nil(t, ca.PopulateFromRoot(&ep, ca.ConfigNode{"name": "api"}))
+	assert.Nil(t, ep.Cache)
+}
+
+type Labelled struct {
+	Name  string
+	Label string
+}
+
+type TaggedLabel struct {
+	Text string `json:"label"`
+}
+
+type ShadowingServer struct {
+	Labelled
+	TaggedLabel
+	Name string
+}
+
+func TestPopulateShadowedFields(t *testing.T) {
+
+	var ss ShadowingServer
+
+	assert.Nil(t, ca.PopulateFromRoot(&ss, ca.ConfigNode{"name": "outer", "label": "l"}, ca.Opts{Strict: true}))
+
+	// A field hides fields with the same key in embedded structs, as with encoding/json
+	assert.EqualValues(t, "outer", ss.Name)
+	assert.Empty(t, ss.Labelled.Name)
+
+	// A tagged field is preferred to an untagged field at the same depth
+	assert.EqualValues(t, "l", ss.Text)
+	assert.Empty(t, ss.Label)
+
+	type Named struct{ Name string }
+
+	var ambiguous struct {
+		Labelled
+		Named
+	}
+
+	// Untagged fields with the same key at the same depth are ambiguous, so neither is used
+	assert.Nil(t, ca.PopulateFromRoot(&ambiguous, ca.ConfigNode{"name": "n"}))
+	assert.Empty(t, ambiguous.Labelled.Name)
+	assert.Empty(t, ambiguous.Named.Name)
+}
+
+func TestConfigTagsWithGet(t *testing.T) {
+
+	node := loadYamlTestFile(t, "tagged.yaml")
+
+	cs := ca.NewDefaultSelector(node, true, true)
+
+	sc, err := ca.Get[ServiceConfig](cs, "service")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 20, sc.PoolSize)
+	assert.EqualValues(t, "DEBUG", sc.LogLevel)
+
+	root := struct {
+		Level    string    `config:"logging.level"`
+		Backends []Backend `config:"service.backends"`
+	}{}
+
+	assert.Nil(t, ca.PopulateFromRoot(&root, node))
+	assert.EqualValues(t, "DEBUG", root.Level)
+	assert.Len(t, root.Backends, 2)
+
+	bad := struct {
+		Level string `config:"logging.level.x"`
+	}{}
+
+	err = ca.PopulateFromRoot(&bad, node)
+	assert.Contains(t, err.Error(), "Level")
+}
+
+type DatabaseDefaults struct {
+	Host    string        `config:"host" required:"true"`
+	Port    int           `config:"port" default:"5432"`
+	Timeout time.Duration `config:"timeout" default:"30s"`
+	Tags    []string      `config:"tags" default:"[\"primary\", \"eu\"]"`
+	Debug   bool          `default:"yes"`
+	Buffer  ca.ByteSize   `default:"64MiB"`
+	Name    string        `default:"unused"`
+	Schema  struct {
+		Name  string `default:"public"`
+		Owner string `required:"true"`
+	}
+	Replica *struct {
+		Host string `required:"true"`
+	}
+}
+
+func TestPopulateDefaults(t *testing.T) {
+
+	node := ca.ConfigNode{"db": ca.ConfigNode{"host": "db.internal", "name": "orders", "port": nil, "schema": ca.ConfigNode{"owner": "app"}}}
+
+	var d DatabaseDefaults
+
+	assert.Nil(t, ca.Populate("db", &d, node))
+	assert.EqualValues(t, "db.internal", d.Host)
+	assert.EqualValues(t, 5432, d.Port)
+	assert.EqualValues(t, 30*time.Second, d.Timeout)
+	assert.EqualValues(t, []string{"primary", "eu"}, d.Tags)
+	assert.True(t, d.Debug)
+	assert.EqualValues(t, 64*ca.MiB, d.Buffer)
+	assert.EqualValues(t, "orders", d.Name)
+	assert.EqualValues(t, "public", d.Schema.Name)
+	assert.EqualValues(t, "app", d.Schema.Owner)
+	assert.Nil(t, d.Replica)
+
+	// Defaults are also applied by Get
+	d, err := ca.Get[DatabaseDefaults](ca.NewDefaultSelector(node, true, true), "db")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5432, d.Port)
+}
+
+func TestPopulateRequired(t *testing.T) {
+
+	node := ca.ConfigNode{"db": ca.ConfigNode{"replica": ca.ConfigNode{}}}
+
+	var d DatabaseDefaults
+
+	err := ca.Populate("db", &d, node)
+	assert.Error(t, err)
+
+	var mpe ca.MissingPathError
+	assert.True(t, errors.As(err, &mpe))
+
+	// Every missing path is reported, including those in nested structs without a value in the config
+	for _, path := range []string{"db.host", "db.Schema.Owner", "db.replica.Host"} {
+		assert.Contains(t, err.Error(), "no value found at "+path)
+	}
+
+	// Defaults are still applied
+	assert.EqualValues(t, 5432, d.Port)
+}
+
+func TestPopulateInvalidRequiredTag(t *testing.T) {
+
+	var bad struct {
+		Host string `required:"yes"`
+		Port int    `required:"ture" default:"80"`
+		Name string `required:"false"`
+	}
+
+	err := ca.PopulateFromRoot(&bad, ca.ConfigNode{"host": "h", "name": "n"})
+
+	// A required tag that is not a bool is reported rather than leaving the field optional
+	assert.Contains(t, err.Error(), `field Host at host: required tag "yes" is not true or false`)
+	assert.Contains(t, err.Error(), `field Port at Port: required tag "ture" is not true or false`)
+	assert.Empty(t, bad.Host)
+	assert.Zero(t, bad.Port)
+	assert.EqualValues(t, "n", bad.Name)
+}
+
+func TestPopulateStrict(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "tagged.json")
+	yamlConf := loadYamlTestFile(t, "tagged.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var sc ServiceConfig
+
+		// Unknown keys are ignored by default
+		assert.Nil(t, ca.Populate("misspelt", &sc, node))
+
+		err := ca.Populate("misspelt", &sc, node, ca.Opts{Strict: true})
+
+		var pe ca.PopulateError
+		assert.True(t, errors.As(err, &pe))
+
+		var unknown []string
+
+		for _, fe := range pe.Fields {
+
+			var uke ca.UnknownKeyError
+			assert.True(t, errors.As(fe, &uke))
+			assert.Equal(t, fe.Path, uke.Path)
+
+			unknown = append(unknown, fe.Path)
+		}
+
+		// Unknown keys in nested structs are reported with the struct's own fields
+		assert.Equal(t, []string{
+			"misspelt.backends[0].wieght",
+			"misspelt.limits.search.burts",
+			"misspelt.cache.tll",
+			"misspelt.http.timeouts.idel",
+			"misspelt.pool.minSise",
+			"misspelt.timout",
+		}, unknown)
+
+		// Known fields are still populated
+		assert.EqualValues(t, 20, sc.PoolSize)
+		assert.EqualValues(t, 5*time.Second, sc.ReadTimeout)
+
+		// Keys of fields tagged config:"-" are unknown
+		err = ca.Populate("service", &sc, node, ca.Opts{Strict: true})
+		assert.True(t, errors.As(err, &pe))
+		assert.Len(t, pe.Fields, 1)
+		assert.EqualValues(t, "service.ignored", pe.Fields[0].Path)
+
+		// Keys used by paths from the root of the config are known
+		err = ca.PopulateFromRoot(&sc, node, ca.Opts{Strict: true})
+		assert.True(t, errors.As(err, &pe))
+		assert.NotContains(t, err.Error(), "value at logging ")
+		assert.Contains(t, err.Error(), "value at service does not map to a field of config_access_test.ServiceConfig")
+
+		var logging struct {
+			Level string `config:"$.logging.level"`
+		}
+
+		assert.Nil(t, ca.PopulateFromRoot(&logging, ca.ConfigNode{"logging": map[string]interface{}{"level": "x"}}, ca.Opts{Strict: true}))
+		assert.EqualValues(t, "x", logging.Level)
+
+		err = ca.PopulateFromRoot(&logging, ca.ConfigNode{"logging": map[string]interface{}{"level": "x", "levle": "y"}}, ca.Opts{Strict: true})
+		assert.True(t, errors.As(err, &pe))
+		assert.Len(t, pe.Fields, 1)
+		assert.EqualValues(t, "logging.levle", pe.Fields[0].Path)
+
+		var named struct {
+			Name string `config:"$.service.name"`
+		}
+
+		// Paths from the root that are inside the object being populated are relative to it
+		err = ca.Populate("service", &named, ca.ConfigNode{"service": map[string]interface{}{"name": "orders"}}, ca.Opts{Strict: true})
+		assert.Nil(t, err)
+	}
+}
+
+func TestPopulateInvalidDefault(t *testing.T) {
+
+	var bad struct {
+		Port int      `default:"none"`
+		Tags []string `default:"[unquoted]"`
+	}
+
+	err := ca.PopulateFromRoot(&bad, ca.ConfigNode{})
+	assert.Contains(t, err.Error(), `field Port at Port: default "none" cannot be used`)
+	assert.Contains(t, err.Error(), "field Tags at Tags: default [unquoted] is not valid JSON")
+}
+
+func TestPopulateBracketedStringDefaults(t *testing.T) {
+
+	var d struct {
+		Mode string  `default:"[auto]"`
+		Tmpl *string `default:"{host}"`
+	}
+
+	// Defaults of fields that are not slices, arrays, maps or structs are not parsed as JSON
+	assert.Nil(t, ca.PopulateFromRoot(&d, ca.ConfigNode{}))
+	assert.EqualValues(t, "[auto]", d.Mode)
+	assert.EqualValues(t, "{host}", *d.Tmpl)
+}
+
+type Severity int
+
+func (s *Severity) UnmarshalText(text []byte) error {
+
+	for i, name := range []string{"debug", "info", "warn", "error"} {
+		if strings.EqualFold(name, string(text)) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown severity %s", text)
+}
+
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// UnmarshalJSON accepts either a string of the form requests/duration or an object
+func (rl *RateLimit) UnmarshalJSON(b []byte) error {
+
+	var s string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+
+		var o struct {
+			Requests int
+			Per      string
+		}
+
+		if err := json.Unmarshal(b, &o); err != nil {
+			return err
+		}
+
+		s = fmt.Sprintf("%d/%s", o.Requests, o.Per)
+	}
+
+	requests, per, _ := strings.Cut(s, "/")
+
+	var err error
+
+	if rl.Requests, err = strconv.Atoi(requests); err != nil {
+		return err
+	}
+
+	rl.Per, err = time.ParseDuration(per)
+
+	return err
+}
+
+type HookedConfig struct {
+	Severity Severity
+	Window   time.Weekday
+	Rate     RateLimit
+	Burst    *RateLimit
+	Alerts   []Severity
+}
+
+func weekdays() *ca.Converters {
+
+	c := ca.NewConverters()
+
+	ca.RegisterConverter(c, func(v interface{}) (time.Weekday, error) {
+
+		s, _ := v.(string)
+
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(s, d.String()) {
+				return d, nil
+			}
+		}
+
+		return 0, fmt.Errorf("%v is not a day of the week", v)
+	})
+
+	return c
+}
+
+func TestPopulateWithUnmarshalers(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "hooks.json")
+	yamlConf := loadYamlTestFile(t, "hooks.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var hc HookedConfig
+
+		assert.Nil(t, ca.Populate("server", &hc, node, ca.Opts{Converters: weekdays()}))
+		assert.EqualValues(t, 2, hc.Severity)
+		assert.EqualValues(t, time.Tuesday, hc.Window)
+		assert.EqualValues(t, RateLimit{Requests: 100, Per: time.Second}, hc.Rate)
+		assert.EqualValues(t, &RateLimit{Requests: 20, Per: 100 * time.Millisecond}, hc.Burst)
+		assert.EqualValues(t, []Severity{3, 0}, hc.Alerts)
+
+		// Without the converter, a string cannot be converted to a time.Weekday
+		assert.Error(t, ca.Populate("server", &hc, node))
+
+		err := ca.Populate("broken", &hc, node, ca.Opts{Converters: weekdays()})
+
+		assert.Equal(t, map[string]string{
+			"Severity":  "broken.severity",
+			"Window":    "broken.window",
+			"Rate":      "broken.rate",
+			"Alerts[0]": "broken.alerts[0]",
+		}, fieldPaths(t, err))
+
+		var ive ca.InvalidValueError
+		assert.True(t, errors.As(err, &ive))
+		assert.Contains(t, err.Error(), "unknown severity loud")
+	}
+}
+
+func TestSetFieldWithUnmarshalers(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "hooks.json")
+	yamlConf := loadYamlTestFile(t, "hooks.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var hc HookedConfig
+
+		assert.Nil(t, ca.SetField("Severity", "server.severity", &hc, node))
+		assert.EqualValues(t, 2, hc.Severity)
+
+		assert.Nil(t, ca.SetField("Rate", "server.burst", &hc, node))
+		assert.EqualValues(t, RateLimit{Requests: 20, Per: 100 * time.Millisecond}, hc.Rate)
+
+		assert.Nil(t, ca.SetField("Window", "server.window", &hc, node, ca.Opts{Converters: weekdays()}))
+		assert.EqualValues(t, time.Tuesday, hc.Window)
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(ca.SetField("Window", "server.window", &hc, node), &tme))
+		assert.EqualValues(t, time.Tuesday, hc.Window)
+
+		assert.True(t, errors.As(ca.SetField("Severity", "broken.alerts[0]", &hc, node), &tme))
+		assert.EqualValues(t, "config_access_test.Severity", tme.Expected)
+		assert.EqualValues(t, 2, hc.Severity)
+
+		// Get and Decode use the same conversions
+		s, err := ca.Get[[]Severity](ca.NewDefaultSelector(node, true, true), "server.alerts")
+		assert.Nil(t, err)
+		assert.EqualValues(t, []Severity{3, 0}, s)
+	}
+}
@@ -0,0 +1,244 @@
+package config_access
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// maxInterpolationDepth limits how deeply references to other config values can be nested, so that cycles that are not
+// detected by comparing paths (e.g. a.b and a["b"]) still terminate
+const maxInterpolationDepth = 32
+
+var stringType = reflect.TypeOf("")
+
+// interpolator expands references of the form ${...} in strings, looking them up in a config tree and the environment
+type interpolator struct {
+	root   ConfigNode
+	access accessor
+	env    func(string) string
+}
+
+func newInterpolator(root ConfigNode, access accessor, opts Opts) *interpolator {
+
+	env := opts.EnvAccessFunc
+
+	if env == nil {
+		env = access.envAccessFunc
+	}
+
+	if env == nil {
+		env = os.Getenv
+	}
+
+	return &interpolator{root: root, access: access, env: env}
+}
+
+// Interpolate expands the references in the supplied string. A reference is written as ${name}, where name is either a
+// path in the supplied config or the name of an environment variable:
+//
+//	${database.host}     the value at the path database.host or, if there is no value at that path, the environment variable
+//	${config:db.host}    only the value at the path db.host
+//	${env:DB_PASS}       only the environment variable DB_PASS
+//	${name:-fallback}    fallback if name is unset or empty (fallback may itself contain references)
+//	${name:?message}     an error with the supplied message if name is unset or empty
+//	$${literal}          the text ${literal}, unexpanded
+//
+// As with a shell, a reference to a value that is unset or empty is replaced with an empty string unless a fallback or
+// error is specified. Values in the config that are numbers or bools are formatted in their shortest form and strings
+// have their own references expanded. A value that refers back to itself, directly or indirectly, is an error. The
+// function for reading environment variables can be overridden with Opts.EnvAccessFunc.
+func Interpolate(s string, node ConfigNode, o ...Opts) (string, error) {
+	return newInterpolator(node, defaultAccessor, options(o)).expand("", s, nil)
+}
+
+// Resolve returns a copy of the supplied config in which the references in every string have been expanded as
+// described in Interpolate. If Opts.Resolvers is set, strings that start with a registered scheme are then resolved.
+func Resolve(node ConfigNode, o ...Opts) (ConfigNode, error) {
+
+	if node == nil {
+		return nil, ErrNilConfig
+	}
+
+	opts := options(o)
+	opts.Interpolate = true
+
+	resolved, err := defaultAccessor.conversion(node, opts).expandAll("", node)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.(ConfigNode), nil
+}
+
+// expand replaces the references in s, which is the value at the supplied path. resolving holds the paths of the config
+// values that are being expanded, to detect cycles.
+func (in *interpolator) expand(path, s string, resolving []string) (string, error) {
+
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			b.WriteString("${")
+			i += 3
+		case strings.HasPrefix(s[i:], "${"):
+			end := closingBrace(s, i+2)
+
+			if end < 0 {
+				return "", InterpolationError{Path: path, Reference: s[i+2:], Err: errors.New("missing closing }")}
+			}
+
+			r, err := in.reference(path, s[i+2:end], resolving)
+
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(r)
+			i = end + 1
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return b.String(), nil
+}
+
+// closingBrace returns the index of the } that closes the reference starting at from, allowing for references nested
+// in fallbacks, or -1 if there is none
+func closingBrace(s string, from int) int {
+
+	depth := 0
+
+	for i := from; i < len(s); i++ {
+
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			i += 2
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+
+			depth--
+		}
+	}
+
+	return -1
+}
+
+func (in *interpolator) reference(path, expr string, resolving []string) (string, error) {
+
+	name, op, arg := expr, "", ""
+
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		name, op, arg = expr[:i], ":-", expr[i+2:]
+	}
+
+	if i := strings.Index(expr, ":?"); i >= 0 && (op == "" || i < len(name)) {
+		name, op, arg = expr[:i], ":?", expr[i+2:]
+	}
+
+	if name == "" {
+		return "", InterpolationError{Path: path, Reference: expr, Err: errors.New("empty reference")}
+	}
+
+	v, err := in.lookup(path, name, resolving)
+
+	if err != nil {
+		return "", err
+	}
+
+	if v != "" {
+		return v, nil
+	}
+
+	switch op {
+	case ":-":
+		return in.expand(path, arg, resolving)
+	case ":?":
+		if arg == "" {
+			arg = "value is required"
+		}
+
+		return "", InterpolationError{Path: path, Reference: name, Err: errors.New(arg)}
+	}
+
+	return "", nil
+}
+
+// lookup returns the value of a reference, or an empty string if it is not set
+func (in *interpolator) lookup(path, name string, resolving []string) (string, error) {
+
+	if n, found := strings.CutPrefix(name, "env:"); found {
+		return in.env(n), nil
+	}
+
+	configOnly := false
+
+	if n, found := strings.CutPrefix(name, "config:"); found {
+		name, configOnly = n, true
+	}
+
+	v, err := in.access.lookup(name, in.root)
+
+	if err != nil {
+
+		if !missing(err) {
+			return "", InterpolationError{Path: path, Reference: name, Err: err}
+		} else if !configOnly {
+			// Names that are not paths in the config are environment variables
+			return in.env(name), nil
+		}
+
+		return "", nil
+	}
+
+	return in.configValue(path, name, v, resolving)
+}
+
+func (in *interpolator) configValue(path, name string, v interface{}, resolving []string) (string, error) {
+
+	if v == nil {
+		return "", nil
+	}
+
+	for _, r := range resolving {
+		if r == name {
+			return "", InterpolationError{Path: path, Reference: name, Err: fmt.Errorf("circular reference (%s -> %s)", strings.Join(resolving, " -> "), name)}
+		}
+	}
+
+	if len(resolving) >= maxInterpolationDepth {
+		return "", InterpolationError{Path: path, Reference: name, Err: fmt.Errorf("references are nested more than %d deep", maxInterpolationDepth)}
+	}
+
+	if s, found := v.(string); found {
+		return in.expand(name, s, append(resolving[:len(resolving):len(resolving)], name))
+	}
+
+	s, err := coerce(name, v, stringType)
+
+	if err != nil {
+		return "", InterpolationError{Path: path, Reference: name, Err: err}
+	}
+
+	if s, found := s.(string); found {
+		return s, nil
+	}
+
+	return "", InterpolationError{Path: path, Reference: name, Err: fmt.Errorf("%s values cannot be interpolated", kindOf(v))}
+}
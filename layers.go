@@ -0,0 +1,163 @@
+package config_access
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// Source provides a layer of configuration that can be combined with other layers by a Loader.
+type Source interface {
+	// Name identifies the source in error messages
+	Name() string
+	// Load returns the source's configuration. Callers are free to modify the returned ConfigNode.
+	Load() (ConfigNode, error)
+}
+
+// FileSource creates a Source that loads the JSON or YAML file at the supplied path with LoadFile.
+func FileSource(path string) Source {
+	return &sourceFunc{
+		name: path,
+		load: func() (ConfigNode, error) {
+			return LoadFile(path)
+		},
+	}
+}
+
+// ReaderSource creates a Source that parses JSON or YAML from the supplied reader with LoadReader. As the reader
+// is consumed, the Source can only be loaded once.
+func ReaderSource(name string, r io.Reader, f Format) Source {
+	return &sourceFunc{
+		name: name,
+		load: func() (ConfigNode, error) {
+			return LoadReader(name, r, f)
+		},
+	}
+}
+
+// NodeSource creates a Source from an already loaded ConfigNode. The node is copied when the Source is loaded, so
+// merging other layers on top of it will not modify the supplied node.
+func NodeSource(name string, node ConfigNode) Source {
+	return &sourceFunc{
+		name: name,
+		load: func() (ConfigNode, error) {
+			return copyNode(node), nil
+		},
+	}
+}
+
+// PathValueSource creates a Source from a map of config paths (e.g. my.config.path) and their associated values,
+// as accepted by SelectorFromPathValues. If a separator is set in the optional SelectorOpts, it is used to split the
+// supplied paths (e.g. "/" for paths of the form my/config/path) and Load returns an InvalidSeparatorError if it is
+// not valid. Other options are ignored.
+func PathValueSource(name string, pathValues map[string]interface{}, o ...SelectorOpts) Source {
+
+	opts := selectorOptions(o)
+
+	return &sourceFunc{
+		name: name,
+		load: func() (ConfigNode, error) {
+
+			if err := opts.Validate(); err != nil {
+				return nil, err
+			}
+
+			return copyNode(nodeFromPathValues(pathValues, newAccessor(opts).separator())), nil
+		},
+	}
+}
+
+type sourceFunc struct {
+	name string
+	load func() (ConfigNode, error)
+}
+
+func (sf *sourceFunc) Name() string {
+	return sf.name
+}
+
+func (sf *sourceFunc) Load() (ConfigNode, error) {
+	return sf.load()
+}
+
+// LayerOpts defines optional behaviour for a layer added to a Loader
+type LayerOpts struct {
+	// If set, a layer whose source does not exist (the Source returns an error matching fs.ErrNotExist) is skipped
+	// rather than causing the load to fail. Other errors (e.g. a file that cannot be parsed) are always returned.
+	Optional bool
+	// If set, arrays in this layer are appended to arrays at the same path in earlier layers rather than replacing them.
+	MergeArrays bool
+}
+
+// Loader combines an ordered list of configuration sources into a single ConfigNode. Each layer is merged on top of
+// the layers added before it using Merge, so values in later layers take precedence.
+type Loader struct {
+	layers []layer
+}
+
+type layer struct {
+	source Source
+	opts   LayerOpts
+}
+
+// NewLoader creates a Loader with no layers.
+func NewLoader() *Loader {
+	return new(Loader)
+}
+
+// Add appends a layer to the loader, returning the loader so calls can be chained.
+func (l *Loader) Add(s Source, o ...LayerOpts) *Loader {
+	var opts LayerOpts
+
+	if len(o) > 0 {
+		opts = o[0]
+	}
+
+	l.layers = append(l.layers, layer{source: s, opts: opts})
+
+	return l
+}
+
+// Load loads each layer in turn and merges them into a single ConfigNode. An error is returned if any required
+// layer cannot be loaded.
+func (l *Loader) Load() (ConfigNode, error) {
+
+	combined := make(ConfigNode)
+
+	for _, ly := range l.layers {
+
+		node, err := ly.source.Load()
+
+		if err != nil {
+
+			if ly.opts.Optional && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("unable to load config layer %s: %w", ly.source.Name(), err)
+		}
+
+		Merge(combined, node, ly.opts.MergeArrays)
+	}
+
+	return combined, nil
+}
+
+// Selector loads and merges all layers and returns a Selector over the result. The Selector returns errors for missing
+// object and array paths, in the same way as a Selector created with SelectorFromPathValues, and is configured with the
+// optional SelectorOpts. An InvalidSeparatorError is returned if the options are not valid.
+func (l *Loader) Selector(o ...SelectorOpts) (Selector, error) {
+
+	if err := selectorOptions(o).Validate(); err != nil {
+		return nil, err
+	}
+
+	node, err := l.Load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDefaultSelector(node, true, true, o...), nil
+}
@@ -0,0 +1,129 @@
+package config_access_test
+
+import (
+	"strings"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredLoading(t *testing.T) {
+
+	base := ca.ConfigNode{"baseOnly": "fromNode", "baseObject": ca.ConfigNode{"objectField3": "node"}}
+
+	s, err := ca.NewLoader().
+		Add(ca.NodeSource("defaults", base)).
+		Add(ca.FileSource("testdata/merge-base.json")).
+		Add(ca.FileSource("testdata/merge-additions.yaml")).
+		Add(ca.FileSource("testdata/not-there.json"), ca.LayerOpts{Optional: true}).
+		Add(ca.PathValueSource("overrides", map[string]interface{}{"baseObject.objectField1": "overridden"})).
+		Selector()
+
+	assert.NoError(t, err)
+
+	v, err := s.StringVal("baseString")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "xyz", v)
+
+	v, err = s.StringVal("baseOnly")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "def", v)
+
+	v, err = s.StringVal("baseObject.objectField1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "overridden", v)
+
+	v, err = s.StringVal("baseObject.objectField3")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "node", v)
+
+	a, err := s.IntArray("baseArray")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{4}, a)
+
+	// The in-memory layer must not have been modified by merging
+	assert.EqualValues(t, "fromNode", base["baseOnly"])
+	assert.Len(t, base["baseObject"], 1)
+}
+
+func TestLayeredLoadingMergeArrays(t *testing.T) {
+
+	node, err := ca.NewLoader().
+		Add(ca.FileSource("testdata/merge-base.yaml")).
+		Add(ca.ReaderSource("extra", strings.NewReader(`{"baseArray": [4]}`), ca.FormatAuto), ca.LayerOpts{MergeArrays: true}).
+		Load()
+
+	assert.NoError(t, err)
+
+	a, err := ca.IntArray("baseArray", node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2, 3, 4}, a)
+}
+
+func TestPathValueSourceIsCopied(t *testing.T) {
+
+	object := map[string]interface{}{"x": 1.0}
+	array := make([]interface{}, 1, 4)
+	array[0] = 1.0
+
+	pv := map[string]interface{}{"object": object, "array": array}
+
+	node, err := ca.NewLoader().
+		Add(ca.PathValueSource("values", pv)).
+		Add(ca.NodeSource("more", ca.ConfigNode{"object": ca.ConfigNode{"y": 2.0}, "array": []interface{}{2.0}}), ca.LayerOpts{MergeArrays: true}).
+		Load()
+
+	assert.NoError(t, err)
+
+	a, err := ca.IntArray("array", node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2}, a)
+	assert.True(t, ca.PathExists("object.y", node))
+
+	// The caller's values must not have been modified by merging
+	assert.Len(t, object, 1)
+	assert.Equal(t, []interface{}{1.0, nil, nil, nil}, array[:4])
+}
+
+func TestLayersWithSeparator(t *testing.T) {
+
+	opts := ca.SelectorOpts{Separator: "/"}
+
+	cs, err := ca.NewLoader().
+		Add(ca.NodeSource("base", ca.ConfigNode{"a": ca.ConfigNode{"b": 0.0, "c": "x"}})).
+		Add(ca.PathValueSource("consul", map[string]interface{}{"a/b": 1.0, "d.e": 2.0}, opts)).
+		Selector(opts)
+
+	assert.NoError(t, err)
+
+	i, err := cs.IntVal("a/b")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, i)
+
+	s, err := cs.StringVal("a/c")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "x", s)
+
+	// The default separator has no special meaning
+	i, err = cs.IntVal("d.e")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, i)
+}
+
+func TestLayeredLoadingErrors(t *testing.T) {
+
+	_, err := ca.NewLoader().
+		Add(ca.FileSource("testdata/merge-base.json")).
+		Add(ca.FileSource("testdata/not-there.json")).
+		Load()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "testdata/not-there.json")
+
+	_, err = ca.NewLoader().
+		Add(ca.FileSource("testdata/broken.json"), ca.LayerOpts{Optional: true}).
+		Load()
+
+	assert.Error(t, err)
+}
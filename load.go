@@ -0,0 +1,266 @@
+package config_access
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the syntax of a configuration source.
+type Format int
+
+const (
+	// FormatAuto indicates that the format should be determined from the source's file extension or, failing that,
+	// by examining its content.
+	FormatAuto Format = iota
+	FormatJSON
+	FormatYAML
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "JSON"
+	case FormatYAML:
+		return "YAML"
+	default:
+		return "auto"
+	}
+}
+
+// ParseError indicates that a configuration source could not be parsed. Line and Column are 1-based and are set
+// to zero if the underlying parser did not report a position.
+type ParseError struct {
+	File   string
+	Format Format
+	Line   int
+	Column int
+	Err    error
+}
+
+func (pe ParseError) Error() string {
+	var b strings.Builder
+
+	if pe.File != "" {
+		b.WriteString(pe.File)
+	} else {
+		b.WriteString("<input>")
+	}
+
+	if pe.Line > 0 {
+		b.WriteString(":" + strconv.Itoa(pe.Line))
+
+		if pe.Column > 0 {
+			b.WriteString(":" + strconv.Itoa(pe.Column))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf(": unable to parse %s: %s", pe.Format, pe.Err.Error()))
+
+	return b.String()
+}
+
+func (pe ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// LoadFile reads and parses the JSON or YAML file at the supplied path. The format is determined by the file's
+// extension (.json, .yaml or .yml) or, if the extension is not recognised, by examining the file's content.
+func LoadFile(path string) (ConfigNode, error) {
+
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadReader(path, f, FormatAuto)
+}
+
+// LoadReader parses JSON or YAML from the supplied reader. The name is used in any error messages and, if the format is
+// FormatAuto, its extension is used to help determine the format of the content.
+func LoadReader(name string, r io.Reader, f Format) (ConfigNode, error) {
+
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(name, data, f)
+}
+
+// Parse converts the supplied JSON or YAML document into a ConfigNode. YAML specific types are normalised so that
+// the result has the same shape as if the document had been parsed with Go's JSON parser: numbers are converted to
+// float64 (unless they are integers too large to be represented exactly), objects with non-string keys are converted
+// to ConfigNodes and timestamps are converted to RFC 3339 strings.
+func Parse(name string, data []byte, f Format) (ConfigNode, error) {
+
+	if f == FormatAuto {
+		f = detectFormat(name, data)
+	}
+
+	switch f {
+	case FormatJSON:
+		return parseJSON(name, data)
+	case FormatYAML:
+		return parseYAML(name, data)
+	default:
+		return nil, fmt.Errorf("unsupported config format %d", f)
+	}
+}
+
+func detectFormat(name string, data []byte) Format {
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+
+	trimmed := bytes.TrimSpace(data)
+
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return FormatJSON
+	}
+
+	return FormatYAML
+}
+
+func parseJSON(name string, data []byte) (ConfigNode, error) {
+
+	var parsed interface{}
+
+	if err := json.Unmarshal(data, &parsed); err != nil {
+
+		pe := ParseError{File: name, Format: FormatJSON, Err: err}
+
+		var se *json.SyntaxError
+		var te *json.UnmarshalTypeError
+
+		if errors.As(err, &se) {
+			// The offset of a syntax error is just past the offending character
+			pe.Line, pe.Column = position(data, se.Offset-1)
+		} else if errors.As(err, &te) {
+			pe.Line, pe.Column = position(data, te.Offset)
+		}
+
+		return nil, pe
+	}
+
+	return rootNode(name, FormatJSON, parsed)
+}
+
+var yamlLine = regexp.MustCompile(`line (\d+)`)
+
+func parseYAML(name string, data []byte) (ConfigNode, error) {
+
+	var parsed interface{}
+
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+
+		pe := ParseError{File: name, Format: FormatYAML, Err: err}
+
+		if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+			pe.Line, _ = strconv.Atoi(m[1])
+		}
+
+		return nil, pe
+	}
+
+	if parsed == nil {
+		return make(ConfigNode), nil
+	}
+
+	return rootNode(name, FormatYAML, parsed)
+}
+
+func rootNode(name string, f Format, parsed interface{}) (ConfigNode, error) {
+
+	if node, found := normalise(parsed).(ConfigNode); found {
+		return node, nil
+	}
+
+	return nil, ParseError{File: name, Format: f, Err: fmt.Errorf("top level of document is %T, not an object", parsed)}
+}
+
+// position converts a byte offset into a 1-based line and column
+func position(data []byte, offset int64) (line, column int) {
+
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	} else if offset < 0 {
+		offset = 0
+	}
+
+	preceding := data[:offset]
+
+	line = bytes.Count(preceding, []byte("\n")) + 1
+	column = int(offset) - bytes.LastIndexByte(preceding, '\n')
+
+	return line, column
+}
+
+// maxExactFloat is the largest integer that can be stored in a float64 without loss of precision
+const maxExactFloat = 1 << 53
+
+func normalise(v interface{}) interface{} {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalise(e)
+		}
+
+		return t
+	case map[interface{}]interface{}:
+		node := make(ConfigNode, len(t))
+
+		for k, e := range t {
+			node[fmt.Sprint(k)] = normalise(e)
+		}
+
+		return node
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalise(e)
+		}
+
+		return t
+	case int:
+		return normaliseInt(int64(t), t)
+	case int64:
+		return normaliseInt(t, t)
+	case uint64:
+		if t <= maxExactFloat {
+			return float64(t)
+		}
+
+		return t
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+func normaliseInt(i int64, original interface{}) interface{} {
+	if i <= maxExactFloat && i >= -maxExactFloat {
+		return float64(i)
+	}
+
+	return original
+}
@@ -0,0 +1,94 @@
+package config_access_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFile(t *testing.T) {
+
+	for _, file := range []string{"testdata/simple.json", "testdata/simple.yaml"} {
+
+		node, err := ca.LoadFile(file)
+		assert.NoError(t, err)
+
+		s, err := ca.StringVal("simpleOne.String", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "abc", s)
+
+		i, err := ca.IntVal("simpleOne.Int", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 32, i)
+
+		f, err := ca.Float64Val("simpleOne.Float", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 32.22, f)
+
+		fa, err := ca.Float64Array("simpleOne.FloatArray", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []float64{1, 2, 3}, fa)
+	}
+}
+
+func TestLoadedYamlMatchesJson(t *testing.T) {
+	jsonConf, err := ca.LoadFile("testdata/merge-base.json")
+	assert.NoError(t, err)
+
+	yamlConf, err := ca.LoadFile("testdata/merge-base.yaml")
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, jsonConf, yamlConf)
+}
+
+func TestLoadReaderDetectsFormat(t *testing.T) {
+
+	node, err := ca.LoadReader("", strings.NewReader(`{"a": {"b": 2}}`), ca.FormatAuto)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, node["a"].(ca.ConfigNode)["b"])
+
+	node, err = ca.LoadReader("", strings.NewReader("a:\n  1: x\n  when: 2001-12-14\n"), ca.FormatAuto)
+	assert.NoError(t, err)
+
+	s, err := ca.StringVal("a.1", node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "x", s)
+
+	s, err = ca.StringVal("a.when", node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "2001-12-14T00:00:00Z", s)
+
+	node, err = ca.LoadReader("empty.yaml", strings.NewReader(""), ca.FormatAuto)
+	assert.NoError(t, err)
+	assert.NotNil(t, node)
+
+	_, err = ca.LoadReader("list.json", strings.NewReader("[1, 2]"), ca.FormatAuto)
+	assert.Error(t, err)
+}
+
+func TestParseErrorPosition(t *testing.T) {
+
+	_, err := ca.LoadFile("testdata/broken.json")
+
+	var pe ca.ParseError
+
+	assert.True(t, errors.As(err, &pe))
+	assert.EqualValues(t, "testdata/broken.json", pe.File)
+	assert.EqualValues(t, ca.FormatJSON, pe.Format)
+	assert.EqualValues(t, 3, pe.Line)
+	assert.EqualValues(t, 12, pe.Column)
+	assert.Contains(t, err.Error(), "testdata/broken.json:3:12")
+
+	_, err = ca.LoadFile("testdata/broken.yaml")
+
+	assert.True(t, errors.As(err, &pe))
+	assert.EqualValues(t, "testdata/broken.yaml", pe.File)
+	assert.EqualValues(t, ca.FormatYAML, pe.Format)
+	assert.EqualValues(t, 3, pe.Line)
+
+	_, err = ca.LoadFile("testdata/missing.json")
+	assert.Error(t, err)
+}
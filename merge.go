@@ -58,3 +58,38 @@ func Merge(base, additional map[string]interface{}, mergeArrays bool) map[string
 func MergeArrays(a []interface{}, b []interface{}) []interface{} {
 	return append(a, b...)
 }
+
+// copyNode makes a deep copy of the objects and arrays in the supplied node so that it can be merged without
+// modifying the original.
+func copyNode(node ConfigNode) ConfigNode {
+
+	if node == nil {
+		return make(ConfigNode)
+	}
+
+	return copyValue(node).(ConfigNode)
+}
+
+func copyValue(v interface{}) interface{} {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		c := make(ConfigNode, len(t))
+
+		for k, e := range t {
+			c[k] = copyValue(e)
+		}
+
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(t))
+
+		for i, e := range t {
+			c[i] = copyValue(e)
+		}
+
+		return c
+	default:
+		return v
+	}
+}
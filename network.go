@@ -0,0 +1,134 @@
+package config_access
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+)
+
+// HostPort is a network address made up of a host (a name or an IP address) and a port, as written in configuration as
+// "db.example.com:5432" or "[::1]:8080".
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// ParseHostPort parses a host and port separated by a colon. IPv6 addresses must be enclosed in square brackets. The
+// host may be empty (e.g. ":8080"), but the port is required.
+func ParseHostPort(s string) (HostPort, error) {
+
+	host, port, err := net.SplitHostPort(s)
+
+	if err != nil {
+		return HostPort{}, err
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid port %q in address %q", port, s)
+	}
+
+	return HostPort{Host: host, Port: uint16(p)}, nil
+}
+
+// String returns the address in the form host:port, enclosing IPv6 addresses in square brackets
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(int(hp.Port)))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (hp HostPort) MarshalText() ([]byte, error) {
+	return []byte(hp.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (hp *HostPort) UnmarshalText(text []byte) error {
+
+	parsed, err := ParseHostPort(string(text))
+
+	if err == nil {
+		*hp = parsed
+	}
+
+	return err
+}
+
+// URLVal returns the absolute URL (one with a scheme, e.g. https://example.com/path) in the string at the supplied path.
+func URLVal(path string, node ConfigNode, o ...Opts) (*url.URL, error) {
+	return decodeAs[*url.URL](defaultAccessor, path, node, options(o))
+}
+
+// HostPortVal returns the host and port in the string at the supplied path (e.g. db.example.com:5432 or [::1]:8080).
+func HostPortVal(path string, node ConfigNode, o ...Opts) (HostPort, error) {
+	return decodeAs[HostPort](defaultAccessor, path, node, options(o))
+}
+
+// IPVal returns the IPv4 or IPv6 address in the string at the supplied path.
+func IPVal(path string, node ConfigNode, o ...Opts) (netip.Addr, error) {
+	return decodeAs[netip.Addr](defaultAccessor, path, node, options(o))
+}
+
+// CIDRVal returns the IP network in CIDR notation (e.g. 10.0.0.0/8) in the string at the supplied path.
+func CIDRVal(path string, node ConfigNode, o ...Opts) (netip.Prefix, error) {
+	return decodeAs[netip.Prefix](defaultAccessor, path, node, options(o))
+}
+
+// URLArray returns the URLs in the array at the supplied path. Each element is interpreted as described in URLVal.
+func URLArray(path string, node ConfigNode, o ...Opts) ([]*url.URL, error) {
+	return decodeAs[[]*url.URL](defaultAccessor, path, node, options(o))
+}
+
+// HostPortArray returns the addresses in the array at the supplied path. Each element is interpreted as described in
+// HostPortVal.
+func HostPortArray(path string, node ConfigNode, o ...Opts) ([]HostPort, error) {
+	return decodeAs[[]HostPort](defaultAccessor, path, node, options(o))
+}
+
+// IPArray returns the IP addresses in the array at the supplied path.
+func IPArray(path string, node ConfigNode, o ...Opts) ([]netip.Addr, error) {
+	return decodeAs[[]netip.Addr](defaultAccessor, path, node, options(o))
+}
+
+// CIDRArray returns the IP networks in the array at the supplied path.
+func CIDRArray(path string, node ConfigNode, o ...Opts) ([]netip.Prefix, error) {
+	return decodeAs[[]netip.Prefix](defaultAccessor, path, node, options(o))
+}
+
+// networkConversion creates a built-in conversion for a type that is parsed from a string
+func networkConversion[T any](name string, parse func(string) (T, error)) func(c conversion, path string, v interface{}) (interface{}, error) {
+
+	return func(c conversion, path string, v interface{}) (interface{}, error) {
+
+		s, found := v.(string)
+
+		if !found {
+			return nil, TypeMismatchError{Path: path, Expected: name, Actual: kindOf(v)}
+		}
+
+		parsed, err := parse(s)
+
+		if err != nil {
+			return nil, InvalidValueError{Path: path, Expected: name, Err: err}
+		}
+
+		return parsed, nil
+	}
+}
+
+func parseAbsoluteURL(s string) (url.URL, error) {
+
+	u, err := url.Parse(s)
+
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	if !u.IsAbs() {
+		return url.URL{}, fmt.Errorf("%q is not an absolute URL", s)
+	}
+
+	return *u, nil
+}
@@ -0,0 +1,244 @@
+package config_access_test
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+type Cluster struct {
+	API     *url.URL
+	Listen  ca.HostPort
+	Trusted []netip.Prefix
+}
+
+type Network struct {
+	Endpoints struct {
+		API     *url.URL
+		Mirrors []*url.URL
+	}
+	Access struct {
+		Gateway   netip.Addr
+		Trusted   netip.Prefix
+		Resolvers []netip.Addr
+	}
+	Clusters map[string]Cluster
+}
+
+func TestURLVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "network.json")
+	yamlConf := loadYamlTestFile(t, "network.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		u, err := cs.URLVal("endpoints.api")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "https", u.Scheme)
+		assert.EqualValues(t, "api.example.com", u.Hostname())
+		assert.EqualValues(t, "8443", u.Port())
+		assert.EqualValues(t, "user", u.User.Username())
+
+		m, err := cs.URLArray("endpoints.mirrors")
+		assert.Nil(t, err)
+		assert.Len(t, m, 2)
+		assert.EqualValues(t, "b.example.com", m[1].Host)
+
+		var ive ca.InvalidValueError
+
+		for _, path := range []string{"endpoints.relative", "endpoints.broken"} {
+			_, err = cs.URLVal(path)
+			assert.True(t, errors.As(err, &ive), path)
+			assert.EqualValues(t, path, ive.Path)
+			assert.EqualValues(t, "URL", ive.Expected)
+		}
+
+		var tme ca.TypeMismatchError
+
+		_, err = cs.URLVal("access.port")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "access.port", tme.Path)
+	}
+}
+
+func TestHostPortVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "network.json")
+	yamlConf := loadYamlTestFile(t, "network.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		hp, err := cs.HostPortVal("listen.public")
+		assert.Nil(t, err)
+		assert.EqualValues(t, ca.HostPort{Host: "0.0.0.0", Port: 8080}, hp)
+
+		hp, err = cs.HostPortVal("listen.ipv6")
+		assert.Nil(t, err)
+		assert.EqualValues(t, ca.HostPort{Host: "::1", Port: 9090}, hp)
+		assert.EqualValues(t, "[::1]:9090", hp.String())
+
+		hp, err = ca.HostPortVal("listen.any", node)
+		assert.Nil(t, err)
+		assert.EqualValues(t, ca.HostPort{Port: 7070}, hp)
+
+		all, err := cs.HostPortArray("listen.all")
+		assert.Nil(t, err)
+		assert.EqualValues(t, []ca.HostPort{{Host: "a", Port: 1}, {Host: "b", Port: 2}}, all)
+
+		var ive ca.InvalidValueError
+
+		for _, path := range []string{"listen.noPort", "listen.badPort"} {
+			_, err = cs.HostPortVal(path)
+			assert.True(t, errors.As(err, &ive), path)
+			assert.EqualValues(t, path, ive.Path)
+		}
+	}
+}
+
+func TestIPAndCIDRVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "network.json")
+	yamlConf := loadYamlTestFile(t, "network.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		ip, err := cs.IPVal("access.gateway")
+		assert.Nil(t, err)
+		assert.EqualValues(t, netip.MustParseAddr("10.0.0.1"), ip)
+
+		ip, err = cs.IPVal("access.gateway6")
+		assert.Nil(t, err)
+		assert.True(t, ip.Is6())
+
+		p, err := cs.CIDRVal("access.trusted")
+		assert.Nil(t, err)
+		assert.True(t, p.Contains(netip.MustParseAddr("10.200.0.1")))
+
+		ps, err := cs.CIDRArray("access.allowed")
+		assert.Nil(t, err)
+		assert.Len(t, ps, 2)
+
+		ips, err := ca.IPArray("access.resolvers", node)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []netip.Addr{netip.MustParseAddr("8.8.8.8"), netip.MustParseAddr("1.1.1.1")}, ips)
+
+		var ive ca.InvalidValueError
+
+		_, err = cs.IPVal("access.badIP")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "access.badIP", ive.Path)
+
+		_, err = cs.CIDRVal("access.badCIDR")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "access.badCIDR", ive.Path)
+
+		_, err = cs.IPArray("access.allowed")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "access.allowed[0]", ive.Path)
+	}
+}
+
+func TestInjectNetworkTypes(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "network.json")
+	yamlConf := loadYamlTestFile(t, "network.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var c Cluster
+
+		assert.Nil(t, ca.SetField("API", "clusters.east.api", &c, node))
+		assert.Nil(t, ca.SetField("Listen", "clusters.east.listen", &c, node))
+		assert.Nil(t, ca.SetField("Trusted", "clusters.east.trusted", &c, node))
+		assert.EqualValues(t, "east.example.com", c.API.Host)
+		assert.EqualValues(t, 1000, c.Listen.Port)
+		assert.EqualValues(t, []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")}, c.Trusted)
+
+		assert.NotNil(t, ca.SetField("API", "endpoints.relative", &c, node))
+
+		var n Network
+
+		assert.Nil(t, ca.PopulateFromRoot(&n, node))
+
+		// Credentials in URLs survive population
+		pw, _ := n.Endpoints.API.User.Password()
+		assert.EqualValues(t, "secret", pw)
+		assert.Len(t, n.Endpoints.Mirrors, 2)
+
+		assert.EqualValues(t, netip.MustParseAddr("10.0.0.1"), n.Access.Gateway)
+		assert.EqualValues(t, netip.MustParsePrefix("10.0.0.0/8"), n.Access.Trusted)
+		assert.Len(t, n.Access.Resolvers, 2)
+
+		assert.Len(t, n.Clusters, 2)
+		assert.EqualValues(t, "west.example.com", n.Clusters["west"].API.Host)
+		assert.EqualValues(t, 2000, n.Clusters["west"].Listen.Port)
+		assert.EqualValues(t, []netip.Prefix{netip.MustParsePrefix("10.2.0.0/16")}, n.Clusters["west"].Trusted)
+	}
+}
+
+func TestNetworkTypesWithOpts(t *testing.T) {
+
+	node := ca.ConfigNode{
+		"api":     "https://${host}/v1",
+		"listen":  "${host}:${port}",
+		"host":    "api.example.com",
+		"port":    8443.0,
+		"subnets": []interface{}{"10.0.0.0/8", "${subnet}"},
+		"subnet":  "192.168.0.0/16",
+		"peers":   []interface{}{"${peer}"},
+		"peer":    "10.1.2.3",
+	}
+
+	opts := ca.Opts{Interpolate: true}
+
+	u, err := ca.URLVal("api", node, opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "api.example.com", u.Host)
+
+	hp, err := ca.HostPortVal("listen", node, opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 8443, hp.Port)
+
+	c, err := ca.CIDRArray("subnets", node, opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "192.168.0.0/16", c[1].String())
+
+	ips, err := ca.IPArray("peers", node, opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "10.1.2.3", ips[0].String())
+
+	_, err = ca.IPVal("peer", node, opts)
+	assert.NoError(t, err)
+
+	_, err = ca.URLVal("api", node)
+	assert.Error(t, err)
+}
+
+func TestQuietNetworkTypes(t *testing.T) {
+
+	var failed []string
+
+	qs := ca.NewDeferredErrorQuietSelector(ca.NewDefaultSelector(loadJsonTestFile(t, "network.json"), true, true), func(path string, err error) {
+		failed = append(failed, path)
+	})
+
+	assert.EqualValues(t, "api.example.com:8443", qs.URLVal("endpoints.api").Host)
+	assert.Nil(t, qs.URLVal("endpoints.relative"))
+	assert.EqualValues(t, 8080, qs.HostPortVal("listen.public").Port)
+	assert.False(t, qs.IPVal("access.badIP").IsValid())
+	assert.True(t, qs.CIDRVal("access.trusted").IsValid())
+	assert.Len(t, qs.CIDRArray("access.allowed"), 2)
+	assert.Nil(t, qs.HostPortArray("listen.missing"))
+
+	assert.EqualValues(t, []string{"endpoints.relative", "access.badIP", "listen.missing"}, failed)
+}
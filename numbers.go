@@ -0,0 +1,148 @@
+package config_access
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// Int64Val returns the int64 value of the number at the supplied path. An error is returned if the value is not a
+// whole number or is outside the range of an int64.
+func Int64Val(path string, node ConfigNode, o ...Opts) (int64, error) {
+	return decodeAs[int64](defaultAccessor, path, node, options(o))
+}
+
+// Int32Val returns the int32 value of the number at the supplied path. An error is returned if the value is not a
+// whole number or is outside the range of an int32.
+func Int32Val(path string, node ConfigNode, o ...Opts) (int32, error) {
+	return decodeAs[int32](defaultAccessor, path, node, options(o))
+}
+
+// Int16Val returns the int16 value of the number at the supplied path. An error is returned if the value is not a
+// whole number or is outside the range of an int16.
+func Int16Val(path string, node ConfigNode, o ...Opts) (int16, error) {
+	return decodeAs[int16](defaultAccessor, path, node, options(o))
+}
+
+// Int8Val returns the int8 value of the number at the supplied path. An error is returned if the value is not a
+// whole number or is outside the range of an int8.
+func Int8Val(path string, node ConfigNode, o ...Opts) (int8, error) {
+	return decodeAs[int8](defaultAccessor, path, node, options(o))
+}
+
+// UintVal returns the uint value of the number at the supplied path. An error is returned if the value is not a
+// whole number, is negative or is outside the range of a uint.
+func UintVal(path string, node ConfigNode, o ...Opts) (uint, error) {
+	return decodeAs[uint](defaultAccessor, path, node, options(o))
+}
+
+// Uint64Val returns the uint64 value of the number at the supplied path. An error is returned if the value is not a
+// whole number, is negative or is outside the range of a uint64.
+func Uint64Val(path string, node ConfigNode, o ...Opts) (uint64, error) {
+	return decodeAs[uint64](defaultAccessor, path, node, options(o))
+}
+
+// Uint32Val returns the uint32 value of the number at the supplied path. An error is returned if the value is not a
+// whole number, is negative or is outside the range of a uint32.
+func Uint32Val(path string, node ConfigNode, o ...Opts) (uint32, error) {
+	return decodeAs[uint32](defaultAccessor, path, node, options(o))
+}
+
+// Uint16Val returns the uint16 value of the number at the supplied path. An error is returned if the value is not a
+// whole number, is negative or is outside the range of a uint16.
+func Uint16Val(path string, node ConfigNode, o ...Opts) (uint16, error) {
+	return decodeAs[uint16](defaultAccessor, path, node, options(o))
+}
+
+// Uint8Val returns the uint8 value of the number at the supplied path. An error is returned if the value is not a
+// whole number, is negative or is outside the range of a uint8.
+func Uint8Val(path string, node ConfigNode, o ...Opts) (uint8, error) {
+	return decodeAs[uint8](defaultAccessor, path, node, options(o))
+}
+
+// convertInteger converts a number found in configuration to the supplied signed or unsigned integer type. Numbers
+// may be float64s (as produced by encoding/json), any of Go's integer types (as produced by YAML parsers) or
+// json.Numbers. Numbers that are not whole or that are outside the range of the type are rejected.
+func convertInteger(path string, v interface{}, t reflect.Type) (reflect.Value, error) {
+
+	i, err := integerValue(v)
+
+	if err != nil {
+		return reflect.Value{}, InvalidValueError{Path: path, Expected: t.String(), Err: err}
+	} else if i == nil {
+		return reflect.Value{}, TypeMismatchError{Path: path, Expected: t.String(), Actual: kindOf(v)}
+	}
+
+	out := reflect.New(t).Elem()
+
+	if out.CanInt() && i.IsInt64() && !out.OverflowInt(i.Int64()) {
+		out.SetInt(i.Int64())
+		return out, nil
+	}
+
+	if out.CanUint() && i.IsUint64() && !out.OverflowUint(i.Uint64()) {
+		out.SetUint(i.Uint64())
+		return out, nil
+	}
+
+	return reflect.Value{}, OutOfRangeError{Path: path, Expected: t.String(), Value: v}
+}
+
+// integerValue returns the supplied number as an integer. A nil integer and nil error are returned if the value is not
+// a number and an error is returned if it is a number, but not a whole number.
+func integerValue(v interface{}) (*big.Int, error) {
+
+	switch n := v.(type) {
+	case float64:
+		return floatInteger(n)
+	case float32:
+		return floatInteger(float64(n))
+	case int:
+		return big.NewInt(int64(n)), nil
+	case int64:
+		return big.NewInt(n), nil
+	case int32:
+		return big.NewInt(int64(n)), nil
+	case int16:
+		return big.NewInt(int64(n)), nil
+	case int8:
+		return big.NewInt(int64(n)), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint64:
+		return new(big.Int).SetUint64(n), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case json.Number:
+		if i, found := new(big.Int).SetString(n.String(), 10); found {
+			return i, nil
+		}
+
+		f, err := n.Float64()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return floatInteger(f)
+	}
+
+	return nil, nil
+}
+
+func floatInteger(f float64) (*big.Int, error) {
+
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%v is not a whole number", f)
+	}
+
+	i, _ := big.NewFloat(f).Int(nil)
+
+	return i, nil
+}
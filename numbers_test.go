@@ -0,0 +1,196 @@
+package config_access_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+type Limits struct {
+	Small    uint8
+	Negative int16
+	Large    uint8
+	Big      int64
+}
+
+func TestSizedIntegers(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "numbers.json")
+	yamlConf := loadYamlTestFile(t, "numbers.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		i8, err := cs.Int8Val("limits.negative")
+		assert.Nil(t, err)
+		assert.EqualValues(t, -5, i8)
+
+		u8, err := cs.Uint8Val("limits.small")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 100, u8)
+
+		i64, err := cs.Int64Val("limits.big")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 5000000000, i64)
+
+		u64, err := cs.Uint64Val("limits.big")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 5000000000, u64)
+
+		i16, err := cs.Int16Val("limits.large")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 300, i16)
+
+		var ore ca.OutOfRangeError
+
+		_, err = cs.Uint8Val("limits.large")
+		assert.True(t, errors.As(err, &ore))
+		assert.EqualValues(t, "limits.large", ore.Path)
+		assert.EqualValues(t, "uint8", ore.Expected)
+		assert.EqualValues(t, "value at limits.large (300) is outside the range of a uint8", err.Error())
+
+		_, err = cs.UintVal("limits.negative")
+		assert.True(t, errors.As(err, &ore))
+
+		_, err = cs.Int32Val("limits.big")
+		assert.True(t, errors.As(err, &ore))
+
+		_, err = cs.Uint16Val("limits.big")
+		assert.True(t, errors.As(err, &ore))
+
+		_, err = cs.Uint32Val("limits.big")
+		assert.True(t, errors.As(err, &ore))
+
+		var ive ca.InvalidValueError
+
+		_, err = cs.IntVal("limits.fraction")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "limits.fraction", ive.Path)
+
+		_, err = cs.Int64Val("limits.fraction")
+		assert.True(t, errors.As(err, &ive))
+
+		_, err = cs.IntArray("limits.mixed")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "limits.mixed[1]", ive.Path)
+
+		var tme ca.TypeMismatchError
+
+		_, err = cs.Uint8Val("limits.text")
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "uint8", tme.Expected)
+		assert.EqualValues(t, "string", tme.Actual)
+
+		u, err := ca.UintVal("limits.small", node)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 100, u)
+
+		var l Limits
+
+		assert.Nil(t, ca.SetField("Small", "limits.small", &l, node))
+		assert.Nil(t, ca.SetField("Negative", "limits.negative", &l, node))
+		assert.Nil(t, ca.SetField("Big", "limits.big", &l, node))
+		assert.EqualValues(t, Limits{Small: 100, Negative: -5, Big: 5000000000}, l)
+
+		assert.True(t, errors.As(ca.SetField("Large", "limits.large", &l, node), &ore))
+		assert.True(t, errors.As(ca.SetField("Big", "limits.fraction", &l, node), &ive))
+	}
+}
+
+func TestLargeAndJSONNumbers(t *testing.T) {
+
+	node, err := ca.Parse("max.yaml", []byte("max: 18446744073709551615\nmin: -9223372036854775808"), ca.FormatYAML)
+	assert.Nil(t, err)
+
+	u, err := ca.Uint64Val("max", node)
+	assert.Nil(t, err)
+	assert.EqualValues(t, uint64(18446744073709551615), u)
+
+	i, err := ca.Int64Val("min", node)
+	assert.Nil(t, err)
+	assert.EqualValues(t, int64(-9223372036854775808), i)
+
+	var ore ca.OutOfRangeError
+
+	_, err = ca.Int64Val("max", node)
+	assert.True(t, errors.As(err, &ore))
+
+	d := json.NewDecoder(bytes.NewReader([]byte(`{"big": 9007199254740993, "fraction": 2.5}`)))
+	d.UseNumber()
+
+	node = ca.ConfigNode{}
+	assert.Nil(t, d.Decode(&node))
+
+	i, err = ca.Int64Val("big", node)
+	assert.Nil(t, err)
+	assert.EqualValues(t, int64(9007199254740993), i)
+
+	var ive ca.InvalidValueError
+
+	_, err = ca.Int32Val("fraction", node)
+	assert.True(t, errors.As(err, &ive))
+
+	f, err := ca.Float64Val("fraction", node)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2.5, f)
+}
+
+func TestQuietSizedIntegers(t *testing.T) {
+
+	var failed []string
+
+	qs := ca.NewDeferredErrorQuietSelector(ca.NewDefaultSelector(loadJsonTestFile(t, "numbers.json"), true, true), func(path string, err error) {
+		failed = append(failed, path)
+	})
+
+	assert.EqualValues(t, 100, qs.Uint8Val("limits.small"))
+	assert.EqualValues(t, -5, qs.Int8Val("limits.negative"))
+	assert.EqualValues(t, 0, qs.Uint8Val("limits.large"))
+	assert.EqualValues(t, 0, qs.IntVal("limits.fraction"))
+
+	assert.EqualValues(t, []string{"limits.large", "limits.fraction"}, failed)
+}
+
+func TestSetFieldRejectsFractionalInts(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "flipped-numbers.json")
+	yamlConf := loadYamlTestFile(t, "flipped-numbers.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var sc SimpleConfig
+
+		var ive ca.InvalidValueError
+
+		assert.True(t, errors.As(ca.SetField("Int", "simpleOne.Int", &sc, node), &ive))
+		assert.EqualValues(t, 0, sc.Int)
+
+		assert.Nil(t, ca.SetField("Float", "simpleOne.Float", &sc, node))
+		assert.EqualValues(t, 32, sc.Float)
+	}
+}
+
+func TestSizedIntegersWithOpts(t *testing.T) {
+
+	node := ca.ConfigNode{"port": "8080", "ref": "${port}", "missing": nil}
+
+	_, err := ca.Uint16Val("port", node)
+	assert.Error(t, err)
+
+	p, err := ca.Uint16Val("port", node, ca.Opts{Coerce: true})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 8080, p)
+
+	i, err := ca.Int64Val("ref", node, ca.Opts{Coerce: true, Interpolate: true})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 8080, i)
+
+	u, err := ca.Uint8Val("absent", node, ca.Opts{OnMissing: uint8(3)})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, u)
+}
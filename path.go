@@ -0,0 +1,597 @@
+package config_access
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	// keySegment is a name in an object or, if the value being traversed is an array, a (possibly negative) index
+	keySegment segmentKind = iota
+	// indexSegment is an index into an array in bracket form, e.g. [1] or [-1]
+	indexSegment
+	// sliceSegment is a range of elements in an array in bracket form, e.g. [1:3] or [:-1]
+	sliceSegment
+	// wildcardSegment matches every member of an object or element of an array (* or [*]). Only supported in queries.
+	wildcardSegment
+	// descentSegment matches the following segment at any depth (an empty element, e.g. http..timeout). Only
+	// supported in queries.
+	descentSegment
+	// filterSegment matches the members of an object or elements of an array for which a filter expression is true
+	// (e.g. [?role=='primary']). Only supported in queries.
+	filterSegment
+)
+
+type pathSegment struct {
+	kind     segmentKind
+	key      string
+	index    int
+	start    int
+	end      int
+	hasStart bool
+	hasEnd   bool
+	// literal is set if the key was quoted or contained escaped characters, so cannot be a wildcard
+	literal bool
+	filter  filterExpression
+}
+
+// parsePath splits a path into its segments. Paths are made up of keys separated by the supplied separator. Elements of arrays
+// can be addressed either with a numeric key (servers.0.host) or with a bracketed index (servers[0].host). Negative
+// indexes count back from the end of the array, so servers[-1] is the last element. A bracketed slice (servers[1:3])
+// selects a range of elements and must be the final segment of a path.
+//
+// Keys that contain the separator or other special characters can either be enclosed in double quotes
+// (hosts."example.com".timeout) or have the special characters escaped with a backslash (hosts.example\.com.timeout).
+func parsePath(path string, separator string) ([]pathSegment, error) {
+
+	segments, err := parseSegments(path, separator)
+
+	if err != nil {
+		return nil, PathSyntaxError{Path: path, Reason: err.Error()}
+	}
+
+	for i, seg := range segments {
+		if seg.kind == sliceSegment && i != len(segments)-1 {
+			return nil, PathSyntaxError{Path: path, Reason: "an array slice must be the last element of a path"}
+		} else if seg.kind == wildcardSegment || seg.kind == filterSegment {
+			return nil, PathSyntaxError{Path: path, Reason: "wildcards and filters are only supported in queries"}
+		}
+	}
+
+	return segments, nil
+}
+
+// parseQuery splits a query into its segments. A query is a path that may also contain wildcards (* or [*]), which
+// match every member of an object or element of an array, and recursive descents (an empty element, e.g.
+// http..timeout), which match the following element at any depth. Array slices may appear anywhere in a query.
+func parseQuery(query string, separator string) ([]pathSegment, error) {
+
+	raw, err := parseSegments(strings.TrimPrefix(query, "$"+separator), separator)
+
+	if err != nil {
+		return nil, PathSyntaxError{Path: query, Reason: err.Error()}
+	}
+
+	var segments []pathSegment
+
+	for _, seg := range raw {
+
+		if seg.kind == keySegment && !seg.literal {
+
+			if seg.key == "*" {
+				seg.kind = wildcardSegment
+			} else if seg.key == "" {
+
+				if len(segments) > 0 && segments[len(segments)-1].kind == descentSegment {
+					continue
+				}
+
+				seg.kind = descentSegment
+			}
+		}
+
+		segments = append(segments, seg)
+	}
+
+	if len(segments) > 0 && segments[len(segments)-1].kind == descentSegment {
+		return nil, PathSyntaxError{Path: query, Reason: "a recursive descent must be followed by another element"}
+	}
+
+	return segments, nil
+}
+
+// parseSegments performs the parsing common to paths and queries
+func parseSegments(path string, separator string) ([]pathSegment, error) {
+
+	var segments []pathSegment
+	var key strings.Builder
+
+	// closed is true when the current segment was a bracket or quoted key, so the next character must start a new segment
+	closed := false
+	segmentStart := true
+	escaped := false
+
+	for i := 0; i < len(path); i++ {
+
+		c := path[i]
+
+		switch {
+		case strings.HasPrefix(path[i:], separator):
+
+			if !closed {
+				segments = append(segments, pathSegment{kind: keySegment, key: key.String(), literal: escaped})
+			}
+
+			key.Reset()
+			escaped = false
+			closed = false
+			segmentStart = true
+			i += len(separator) - 1
+			continue
+
+		case c == '[':
+
+			if key.Len() > 0 {
+				segments = append(segments, pathSegment{kind: keySegment, key: key.String(), literal: escaped})
+				key.Reset()
+				escaped = false
+			}
+
+			closing := closingBracket(path[i:])
+
+			if closing == -1 {
+				return nil, fmt.Errorf("unterminated [ at position %d", i)
+			}
+
+			seg, err := parseBracket(path[i+1:i+closing], separator)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid expression in brackets at position %d: %s", i, err.Error())
+			}
+
+			segments = append(segments, seg)
+			closed = true
+			i += closing
+
+		case closed:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+
+		case c == '"' && segmentStart:
+
+			quoted, length, err := unquote(path[i:])
+
+			if err != nil {
+				return nil, fmt.Errorf("%s at position %d", err.Error(), i)
+			}
+
+			segments = append(segments, pathSegment{kind: keySegment, key: quoted, literal: true})
+			closed = true
+			i += length - 1
+
+		case c == '\\':
+
+			if i+1 == len(path) {
+				return nil, fmt.Errorf("incomplete escape sequence at position %d", i)
+			}
+
+			i++
+			key.WriteByte(path[i])
+			escaped = true
+
+		default:
+			key.WriteByte(c)
+		}
+
+		segmentStart = false
+	}
+
+	if !closed {
+		segments = append(segments, pathSegment{kind: keySegment, key: key.String(), literal: escaped})
+	}
+
+	return segments, nil
+}
+
+// pathKeys parses a path that is made up only of object keys
+func pathKeys(path string, separator string) ([]string, error) {
+
+	segments, err := parsePath(path, separator)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(segments))
+
+	for i, seg := range segments {
+
+		if seg.kind != keySegment {
+			return nil, PathSyntaxError{Path: path, Reason: "array indexes are not supported in this context"}
+		}
+
+		keys[i] = seg.key
+	}
+
+	return keys, nil
+}
+
+// unquote reads a double quoted key from the start of the supplied string, returning the key and the number of bytes
+// consumed (including the quotes)
+func unquote(s string) (string, int, error) {
+
+	var key strings.Builder
+
+	for i := 1; i < len(s); i++ {
+
+		switch s[i] {
+		case '"':
+			return key.String(), i + 1, nil
+		case '\\':
+			if i+1 == len(s) {
+				break
+			}
+
+			i++
+			key.WriteByte(s[i])
+		default:
+			key.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated quoted key")
+}
+
+// closingBracket returns the index of the ] that closes the [ at the start of the supplied string, ignoring
+// brackets in nested brackets or quoted strings. Returns -1 if the bracket is not closed.
+func closingBracket(s string) int {
+
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+
+		c := s[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func parseBracket(content string, separator string) (pathSegment, error) {
+
+	content = strings.TrimSpace(content)
+
+	if content == "*" {
+		return pathSegment{kind: wildcardSegment}, nil
+	}
+
+	if strings.HasPrefix(content, "?") {
+
+		f, err := parseFilter(content[1:], separator)
+
+		return pathSegment{kind: filterSegment, filter: f}, err
+	}
+
+	if colon := strings.IndexByte(content, ':'); colon != -1 {
+
+		seg := pathSegment{kind: sliceSegment}
+
+		var err error
+
+		if s := strings.TrimSpace(content[:colon]); s != "" {
+			if seg.start, err = strconv.Atoi(s); err != nil {
+				return seg, err
+			}
+
+			seg.hasStart = true
+		}
+
+		if e := strings.TrimSpace(content[colon+1:]); e != "" {
+			if seg.end, err = strconv.Atoi(e); err != nil {
+				return seg, err
+			}
+
+			seg.hasEnd = true
+		}
+
+		return seg, nil
+	}
+
+	i, err := strconv.Atoi(content)
+
+	return pathSegment{kind: indexSegment, index: i}, err
+}
+
+// BuildPath creates a path from the supplied elements. String elements are treated as keys and are quoted if they
+// contain characters that have a special meaning in paths (see QuoteKey). Int elements are treated as array indexes.
+// Any other type of element is converted to a string with fmt.Sprint and treated as a key.
+//
+// For example, BuildPath("hosts", "example.com", "ports", 0) returns hosts."example.com".ports[0]
+//
+// Keys are joined with PathSeparator. Use BuildPathWithSeparator to build paths for a Selector with a different
+// separator.
+func BuildPath(elements ...interface{}) string {
+	return buildPath(PathSeparator, elements...)
+}
+
+// BuildPathWithSeparator behaves like BuildPath, except keys are joined with the supplied separator (as set in
+// SelectorOpts.Separator) and quoted if they contain it. If the separator is empty, PathSeparator is used.
+//
+// For example, BuildPathWithSeparator("/", "hosts", "example.com", "a/b") returns hosts/example.com/"a/b"
+func BuildPathWithSeparator(separator string, elements ...interface{}) string {
+
+	if separator == "" {
+		separator = PathSeparator
+	}
+
+	return buildPath(separator, elements...)
+}
+
+func buildPath(separator string, elements ...interface{}) string {
+
+	var b strings.Builder
+
+	for i, e := range elements {
+
+		if index, found := e.(int); found {
+			b.WriteString("[" + strconv.Itoa(index) + "]")
+			continue
+		}
+
+		if i > 0 {
+			b.WriteString(separator)
+		}
+
+		key, found := e.(string)
+
+		if !found {
+			key = fmt.Sprint(e)
+		}
+
+		b.WriteString(quoteKey(key, separator))
+	}
+
+	return b.String()
+}
+
+// QuoteKey returns the supplied key in a form that can be used as a single element of a path. Keys that are empty,
+// contain the path separator, brackets, quotes or backslashes or are * or $ (which would otherwise be read as a
+// wildcard or the root of the config) are enclosed in double quotes, with any quotes or backslashes in the key escaped
+// with a backslash. Other keys are returned unchanged.
+func QuoteKey(key string) string {
+	return quoteKey(key, PathSeparator)
+}
+
+// QuoteKeyWithSeparator behaves like QuoteKey, except keys are quoted if they contain the supplied separator rather
+// than PathSeparator. If the separator is empty, PathSeparator is used.
+func QuoteKeyWithSeparator(key string, separator string) string {
+
+	if separator == "" {
+		separator = PathSeparator
+	}
+
+	return quoteKey(key, separator)
+}
+
+func quoteKey(key string, separator string) string {
+
+	if key != "" && key != "*" && key != "$" && !strings.Contains(key, separator) && !strings.ContainsAny(key, `[]"\`) {
+		return key
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('"')
+
+	for i := 0; i < len(key); i++ {
+		if key[i] == '"' || key[i] == '\\' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(key[i])
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// walk follows the supplied segments from the supplied value, returning nil if any segment does not match
+func walk(segments []pathSegment, value interface{}) interface{} {
+
+	result, _ := follow(segments, value)
+
+	return result
+}
+
+// pathStop records where and why a path could not be followed
+type pathStop struct {
+	// The index of the segment that could not be followed
+	position int
+	// The concrete keys and indexes that were followed before the path stopped matching
+	elements []interface{}
+	// The value the segment could not be applied to
+	found interface{}
+	// Set if the value was an object or array of the right kind, but did not contain a matching element
+	missing bool
+}
+
+// follow follows the supplied segments from the supplied value. If the path ends at a null or missing value or a segment
+// cannot be applied to the value it is followed from, nil is returned with a description of where the path stopped matching.
+func follow(segments []pathSegment, value interface{}) (interface{}, *pathStop) {
+
+	var elements []interface{}
+
+	for i, seg := range segments {
+
+		next, element, applicable := step(seg, value)
+
+		if next == nil {
+			return nil, &pathStop{position: i, elements: elements, found: value, missing: applicable}
+		}
+
+		elements = appendElement(elements, element)
+		value = next
+	}
+
+	return value, nil
+}
+
+// step follows a single key, index or slice segment from the supplied value. It returns the value found (or nil if the
+// segment does not match), the concrete key (a string) or array index (an int) that was followed and whether or not
+// the segment could be applied to the type of the supplied value.
+func step(seg pathSegment, value interface{}) (interface{}, interface{}, bool) {
+
+	switch current := value.(type) {
+	case map[string]interface{}:
+
+		if seg.kind != keySegment {
+			return nil, nil, false
+		}
+
+		return current[seg.key], seg.key, true
+
+	case map[interface{}]interface{}:
+
+		if seg.kind != keySegment {
+			return nil, nil, false
+		}
+
+		for k, v := range current {
+			if fmt.Sprint(k) == seg.key {
+				return v, seg.key, true
+			}
+		}
+
+		return nil, nil, true
+
+	case []interface{}:
+
+		switch seg.kind {
+		case keySegment:
+			i, err := strconv.Atoi(seg.key)
+
+			if err != nil {
+				return nil, nil, false
+			}
+
+			v, e := element(current, i)
+
+			return v, e, true
+		case indexSegment:
+			v, e := element(current, seg.index)
+
+			return v, e, true
+		case sliceSegment:
+			start, end := sliceBounds(len(current), seg)
+
+			// Copied so that modifying the result does not modify the config
+			elements := make([]interface{}, end-start)
+			copy(elements, current[start:end])
+
+			return elements, nil, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// segmentText returns the supplied segment as it would appear in a path
+func segmentText(seg pathSegment, separator string) string {
+
+	switch seg.kind {
+	case indexSegment:
+		return "[" + strconv.Itoa(seg.index) + "]"
+	case sliceSegment:
+		var b strings.Builder
+
+		b.WriteByte('[')
+
+		if seg.hasStart {
+			b.WriteString(strconv.Itoa(seg.start))
+		}
+
+		b.WriteByte(':')
+
+		if seg.hasEnd {
+			b.WriteString(strconv.Itoa(seg.end))
+		}
+
+		b.WriteByte(']')
+
+		return b.String()
+	default:
+		return quoteKey(seg.key, separator)
+	}
+}
+
+// element returns the element of the array at the supplied index (counting back from the end of the array if the index
+// is negative) and the equivalent positive index
+func element(a []interface{}, i int) (interface{}, interface{}) {
+
+	if i < 0 {
+		i += len(a)
+	}
+
+	if i < 0 || i >= len(a) {
+		return nil, nil
+	}
+
+	return a[i], i
+}
+
+// sliceBounds converts the range in a slice segment into bounds that are valid for an array of the supplied length
+func sliceBounds(l int, seg pathSegment) (int, int) {
+
+	start, end := 0, l
+
+	if seg.hasStart {
+		start = clampIndex(seg.start, l)
+	}
+
+	if seg.hasEnd {
+		end = clampIndex(seg.end, l)
+	}
+
+	if start > end {
+		start = end
+	}
+
+	return start, end
+}
+
+func clampIndex(i, l int) int {
+
+	if i < 0 {
+		i += l
+	}
+
+	if i < 0 {
+		return 0
+	} else if i > l {
+		return l
+	}
+
+	return i
+}
@@ -0,0 +1,227 @@
+package config_access_test
+
+import (
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+type Server struct {
+	Host string
+	Port int
+	Tags []string
+}
+
+func TestArrayIndexPaths(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "arrays.json")
+	yamlConf := loadYamlTestFile(t, "arrays.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		s, err := ca.StringVal("servers.0.host", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "alpha", s)
+
+		i, err := ca.IntVal("servers[1].port", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 8081, i)
+
+		s, err = ca.StringVal("servers[-1].host", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "gamma", s)
+
+		s, err = ca.StringVal("servers.-2.tags[0]", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "c", s)
+
+		i, err = ca.IntVal("matrix[1][0]", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 3, i)
+
+		assert.True(t, ca.PathExists("servers[2]", node))
+		assert.False(t, ca.PathExists("servers[3]", node))
+		assert.False(t, ca.PathExists("servers[-4]", node))
+		assert.False(t, ca.PathExists("servers.x", node))
+		assert.False(t, ca.PathExists("servers[x]", node))
+		assert.False(t, ca.PathExists("servers[0", node))
+		assert.False(t, ca.PathExists("servers[0]host", node))
+		assert.False(t, ca.PathExists("names[0].host", node))
+	}
+}
+
+func TestArraySlicePaths(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "arrays.json")
+	yamlConf := loadYamlTestFile(t, "arrays.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		sa, err := ca.StringArray("names[1:]", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"y", "z"}, sa)
+
+		sa, err = ca.StringArray("names[:-1]", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"x", "y"}, sa)
+
+		sa, err = ca.StringArray("names[1:2]", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"y"}, sa)
+
+		sa, err = ca.StringArray("names[2:1]", node)
+		assert.NoError(t, err)
+		assert.Empty(t, sa)
+
+		assert.False(t, ca.PathExists("servers[0:1].host", node))
+
+		// Slices are copies, so modifying them does not modify the config
+		names := ca.Value("names[0:2]", node).([]interface{})
+		names[0] = "modified"
+		_ = append(names, "appended")
+
+		sa, err = ca.StringArray("names", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"x", "y", "z"}, sa)
+	}
+}
+
+func TestArrayIndexPathsViaSelectors(t *testing.T) {
+
+	var invoked bool
+
+	errorFunc := func(path string, err error) {
+		invoked = true
+	}
+
+	jsonConf := loadJsonTestFile(t, "arrays.json")
+	yamlConf := loadYamlTestFile(t, "arrays.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+		qs := ca.NewDeferredErrorQuietSelector(cs, errorFunc)
+
+		o, err := cs.ObjectVal("servers[0]")
+		assert.NoError(t, err)
+		assert.EqualValues(t, "alpha", o["host"])
+
+		sa, err := cs.StringArray("servers[0].tags")
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"a", "b"}, sa)
+
+		assert.EqualValues(t, 8082, qs.IntVal("servers[-1].port"))
+		assert.False(t, invoked)
+
+		assert.EqualValues(t, 0, qs.IntVal("servers[5].port"))
+		assert.True(t, invoked)
+		invoked = false
+
+		var sv Server
+
+		err = ca.SetField("Host", "servers[1].host", &sv, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "beta", sv.Host)
+
+		err = ca.Populate("servers.2", &sv, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "gamma", sv.Host)
+		assert.EqualValues(t, 8082, sv.Port)
+	}
+}
+
+func TestQuotedAndEscapedPaths(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "dotted-keys.json")
+	yamlConf := loadYamlTestFile(t, "dotted-keys.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		i, err := ca.IntVal(`hosts."example.com".timeout`, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 30, i)
+
+		i, err = ca.IntVal(`hosts.example\.com.timeout`, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 30, i)
+
+		s, err := ca.StringVal(`hosts."example.com".aliases[0]`, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "www.example.com", s)
+
+		s, err = ca.StringVal(`loggers."log.level"`, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "debug", s)
+
+		s, err = ca.StringVal(`hosts."quote\"d"`, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "q", s)
+
+		s, err = ca.StringVal(`hosts.back\\slash`, node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "b", s)
+
+		assert.False(t, ca.PathExists("hosts.example.com.timeout", node))
+		assert.False(t, ca.PathExists(`hosts."example.com.timeout`, node))
+		assert.False(t, ca.PathExists(`hosts."example.com"timeout`, node))
+		assert.False(t, ca.PathExists(`hosts.example\`, node))
+	}
+}
+
+func TestBuildPath(t *testing.T) {
+
+	node := loadJsonTestFile(t, "dotted-keys.json")
+
+	p := ca.BuildPath("hosts", "example.com", "aliases", 0)
+	assert.EqualValues(t, `hosts."example.com".aliases[0]`, p)
+
+	s, err := ca.StringVal(p, node)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "www.example.com", s)
+
+	for _, key := range []string{`quote"d`, `back\slash`} {
+		s, err = ca.StringVal(ca.BuildPath("hosts", key), node)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, s)
+	}
+
+	assert.EqualValues(t, "a.b.c", ca.BuildPath("a", "b", "c"))
+	assert.EqualValues(t, `a."".c[-1]`, ca.BuildPath("a", "", "c", -1))
+	assert.EqualValues(t, "plain", ca.QuoteKey("plain"))
+	assert.EqualValues(t, `"x[1]"`, ca.QuoteKey("x[1]"))
+}
+
+func TestBuiltPathsRoundTrip(t *testing.T) {
+
+	node := ca.ConfigNode{
+		"$":     map[string]interface{}{"a": 1.0},
+		"weird": map[string]interface{}{"*": 2.0, "other": 3.0},
+	}
+
+	for _, elements := range [][]interface{}{{"$", "a"}, {"weird", "*"}} {
+
+		p := ca.BuildPath(elements...)
+
+		// Keys that are wildcards or the root of the config are quoted so that they are only matched literally
+		pvs, err := ca.Select(p, node)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 1)
+		assert.EqualValues(t, p, pvs[0].Path)
+	}
+
+	pvs, err := ca.Select("weird.*", node)
+	assert.NoError(t, err)
+	assert.Contains(t, paths(pvs), `weird."*"`)
+
+	for _, pv := range pvs {
+
+		again, err := ca.Select(pv.Path, node)
+		assert.NoError(t, err)
+		assert.Len(t, again, 1)
+		assert.EqualValues(t, pv.Value, again[0].Value)
+	}
+
+	assert.EqualValues(t, `"*"`, ca.QuoteKey("*"))
+	assert.EqualValues(t, `"$"`, ca.QuoteKey("$"))
+}
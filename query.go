@@ -0,0 +1,250 @@
+package config_access
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// PathValue is a value found by a query and the concrete path at which it was found
+type PathValue struct {
+	Path  string
+	Value interface{}
+}
+
+// Select finds every value in the supplied node that matches the supplied query. A query uses the same syntax as
+// a path, but may also contain:
+//
+// Wildcards (* or [*]), which match every member of an object or element of an array, e.g. services.*.port
+//
+// Recursive descents (an empty path element), which match the following element at any depth, e.g. http..timeout
+//
+// Array slices (e.g. servers[0:2].host) in any position.
+//
+// Filters (e.g. databases[?role=='primary'].host), which match the members of an object or elements of an array for
+// which the filter expression is true. See Query for the syntax of filter expressions.
+//
+// A query may optionally start with $ followed by the separator (e.g. $.services.*.port) to indicate the root of the config.
+//
+// The matched values are returned with their concrete paths (e.g. services.auth.port) in a deterministic order: object
+// members in the lexical order of their keys and array elements in index order. Null values are not matched.
+// An error is returned if the query cannot be parsed.
+func Select(query string, node ConfigNode) ([]PathValue, error) {
+	return defaultAccessor.selectPaths(query, node)
+}
+
+func (a accessor) selectPaths(query string, node ConfigNode) ([]PathValue, error) {
+
+	if node == nil {
+		return nil, ErrNilConfig
+	}
+
+	segments, err := parseQuery(query, a.separator())
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []PathValue
+
+	match(segments, node, nil, func(elements []interface{}, v interface{}) {
+		results = append(results, PathValue{Path: buildPath(a.separator(), elements...), Value: v})
+	})
+
+	return results, nil
+}
+
+// match applies the supplied segments to the supplied value, calling found with the path elements (string keys and int
+// indexes) and value of each match
+func match(segments []pathSegment, value interface{}, elements []interface{}, found func([]interface{}, interface{})) {
+
+	if value == nil {
+		return
+	}
+
+	if len(segments) == 0 {
+		found(elements, value)
+		return
+	}
+
+	seg := segments[0]
+	remaining := segments[1:]
+
+	switch seg.kind {
+	case descentSegment:
+		// The following segment might match here or in any descendant
+		match(remaining, value, elements, found)
+
+		eachChild(value, elements, func(childElements []interface{}, child interface{}) {
+			match(segments, child, childElements, found)
+		})
+
+	case wildcardSegment:
+		eachChild(value, elements, func(childElements []interface{}, child interface{}) {
+			match(remaining, child, childElements, found)
+		})
+
+	case filterSegment:
+		eachChild(value, elements, func(childElements []interface{}, child interface{}) {
+			if seg.filter.matches(child) {
+				match(remaining, child, childElements, found)
+			}
+		})
+
+	case sliceSegment:
+		if a, isArray := value.([]interface{}); isArray {
+
+			start, end := sliceBounds(len(a), seg)
+
+			for i := start; i < end; i++ {
+				match(remaining, a[i], appendElement(elements, i), found)
+			}
+		}
+
+	default:
+		if child, element, _ := step(seg, value); child != nil {
+			match(remaining, child, appendElement(elements, element), found)
+		}
+	}
+}
+
+// eachChild calls the supplied function for each member of an object (in key order) or element of an array
+func eachChild(value interface{}, elements []interface{}, f func([]interface{}, interface{})) {
+
+	switch current := value.(type) {
+	case map[interface{}]interface{}:
+
+		keys := make([]string, 0, len(current))
+		values := make(map[string]interface{}, len(current))
+
+		for k, v := range current {
+			key := fmt.Sprint(k)
+			keys = append(keys, key)
+			values[key] = v
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			f(appendElement(elements, k), values[k])
+		}
+
+	case map[string]interface{}:
+
+		keys := make([]string, 0, len(current))
+
+		for k := range current {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			f(appendElement(elements, k), current[k])
+		}
+
+	case []interface{}:
+
+		for i, v := range current {
+			f(appendElement(elements, i), v)
+		}
+	}
+}
+
+// appendElement returns a copy of the supplied path elements with an additional element
+func appendElement(elements []interface{}, e interface{}) []interface{} {
+	return append(elements[:len(elements):len(elements)], e)
+}
+
+// Query evaluates a query expression against the supplied node. Query expressions use the same syntax as Select, including
+// filters. A filter is a bracketed expression starting with ? that is evaluated against each member of an object or
+// element of an array:
+//
+//	databases[?role=='primary'].host
+//	servers[?port >= 8000 && !(@.disabled)].name
+//	users[?tags[0] == "admin" || level > 3]
+//
+// Within a filter, paths are relative to the value being filtered, either as a bare path (role) or starting with @
+// (@.role). @ on its own refers to the value being filtered. Paths can be compared with string literals ('a' or "a"),
+// numbers, true, false and null or with other paths using ==, !=, <, <=, > and >=, and comparisons can be combined
+// with &&, || and ! and grouped with parentheses. A path on its own is true if it refers to a value other than null or false.
+// Numbers are compared numerically and strings lexically, values of different types are never equal and a missing
+// value is equal to null.
+func Query(expression string, node ConfigNode) (QueryResult, error) {
+	return defaultAccessor.query(expression, node)
+}
+
+func (a accessor) query(expression string, node ConfigNode) (QueryResult, error) {
+
+	matches, err := a.selectPaths(expression, node)
+
+	return QueryResult{matches: matches}, err
+}
+
+// QueryResult holds the values matched by a query expression, in the order described in Select.
+type QueryResult struct {
+	matches []PathValue
+}
+
+// Matches returns each matched value with its concrete path
+func (qr QueryResult) Matches() []PathValue {
+	return qr.matches
+}
+
+// Len returns the number of values that were matched
+func (qr QueryResult) Len() int {
+	return len(qr.matches)
+}
+
+// Values returns the matched values
+func (qr QueryResult) Values() []interface{} {
+
+	values := make([]interface{}, len(qr.matches))
+
+	for i, m := range qr.matches {
+		values[i] = m.Value
+	}
+
+	return values
+}
+
+// StringArray returns the matched values as strings. An error naming the path of the first value that is not a string
+// is returned.
+func (qr QueryResult) StringArray() ([]string, error) {
+	return convertMatches[string](qr.matches)
+}
+
+// IntArray returns the matched values as ints. An error naming the path of the first value that is not a whole number
+// in the range of an int is returned.
+func (qr QueryResult) IntArray() ([]int, error) {
+	return convertMatches[int](qr.matches)
+}
+
+// Float64Array returns the matched values as float64s. An error naming the path of the first value that is not a
+// number is returned.
+func (qr QueryResult) Float64Array() ([]float64, error) {
+	return convertMatches[float64](qr.matches)
+}
+
+// convertMatches converts each matched value to a T in the same way as the accessors, naming the value's concrete path
+// in any error
+func convertMatches[T any](matches []PathValue) ([]T, error) {
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	c := defaultAccessor.conversion(nil, Opts{})
+
+	typedVal := make([]T, len(matches))
+
+	for i, m := range matches {
+
+		cv, err := c.convert(m.Path, m.Value, t)
+
+		if err != nil {
+			return nil, err
+		}
+
+		typedVal[i] = cv.Interface().(T)
+	}
+
+	return typedVal, nil
+}
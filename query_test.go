@@ -0,0 +1,115 @@
+package config_access_test
+
+import (
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func paths(pvs []ca.PathValue) []string {
+	p := make([]string, len(pvs))
+
+	for i, pv := range pvs {
+		p[i] = pv.Path
+	}
+
+	return p
+}
+
+func TestWildcardQuery(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "services.json")
+	yamlConf := loadYamlTestFile(t, "services.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		pvs, err := ca.Select("services.*.port", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"services.auth.port", "services.billing.port", "services.search.port"}, paths(pvs))
+		assert.EqualValues(t, 8002, pvs[1].Value)
+
+		pvs, err = ca.Select("services.search.replicas[*].host", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"services.search.replicas[0].host", "services.search.replicas[1].host"}, paths(pvs))
+
+		pvs, err = ca.Select("services.search.replicas[1:].host", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"services.search.replicas[1].host"}, paths(pvs))
+
+		pvs, err = ca.Select(`services."*"`, node)
+		assert.NoError(t, err)
+		assert.Empty(t, pvs)
+
+		pvs, err = ca.Select("services.auth.port", node)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 1)
+
+		// Concrete paths can be used with the other accessors
+		for _, pv := range pvs {
+			i, err := ca.IntVal(pv.Path, node)
+			assert.NoError(t, err)
+			assert.EqualValues(t, pv.Value, i)
+		}
+	}
+}
+
+func TestRecursiveDescentQuery(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "services.json")
+	yamlConf := loadYamlTestFile(t, "services.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		pvs, err := ca.Select("http..timeout", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{
+			"http.timeout",
+			"http.client.timeout",
+			"http.client.retry.timeout",
+			"http.routes[0].timeout",
+		}, paths(pvs))
+
+		pvs, err = ca.Select("..timeout", node)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 7)
+
+		pvs, err = ca.Select("services...replicas..host", node)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"services.search.replicas[0].host", "services.search.replicas[1].host"}, paths(pvs))
+
+		_, err = ca.Select("http..", node)
+		assert.Error(t, err)
+
+		_, err = ca.Select("http[", node)
+		assert.Error(t, err)
+
+		_, err = ca.Select("http", nil)
+		assert.Error(t, err)
+	}
+}
+
+func TestSelectViaSelector(t *testing.T) {
+
+	node := loadJsonTestFile(t, "dotted-keys.json")
+
+	cs := ca.NewDefaultSelector(node, true, true)
+
+	pvs, err := cs.Select("hosts.*.timeout")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{`hosts."example.com".timeout`}, paths(pvs))
+
+	cs = ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Separator: "/"})
+
+	pvs, err = cs.Select("hosts//aliases[-1]")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"hosts/example.com/aliases[0]"}, paths(pvs))
+
+	s, err := cs.StringVal(pvs[0].Path)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "www.example.com", s)
+
+	// Wildcards are not supported outside of queries
+	_, err = cs.StringVal("hosts/*/aliases[*]")
+	assert.Error(t, err)
+}
@@ -1,5 +1,11 @@
 package config_access
 
+import (
+	"net/netip"
+	"net/url"
+	"time"
+)
+
 // QuietSelector does not return errors for missing paths or incompatible types
 type QuietSelector interface {
 	PathExists(path string) bool
@@ -10,10 +16,38 @@ type QuietSelector interface {
 	IntArray(path string, o ...Opts) []int
 	Float64Array(path string, o ...Opts) []float64
 	IntVal(path string, o ...Opts) int
+	Int64Val(path string, o ...Opts) int64
+	Int32Val(path string, o ...Opts) int32
+	Int16Val(path string, o ...Opts) int16
+	Int8Val(path string, o ...Opts) int8
+	UintVal(path string, o ...Opts) uint
+	Uint64Val(path string, o ...Opts) uint64
+	Uint32Val(path string, o ...Opts) uint32
+	Uint16Val(path string, o ...Opts) uint16
+	Uint8Val(path string, o ...Opts) uint8
 	Float64Val(path string, o ...Opts) float64
 	Array(path string, o ...Opts) []interface{}
 	BoolVal(path string, o ...Opts) bool
 	StringOrEnv(path string, o ...Opts) string
+	DurationVal(path string, o ...Opts) time.Duration
+	TimeVal(path string, o ...Opts) time.Time
+	DurationArray(path string, o ...Opts) []time.Duration
+	TimeArray(path string, o ...Opts) []time.Time
+	ByteSizeVal(path string, o ...Opts) ByteSize
+	QuantityVal(path string, o ...Opts) float64
+	URLVal(path string, o ...Opts) *url.URL
+	HostPortVal(path string, o ...Opts) HostPort
+	IPVal(path string, o ...Opts) netip.Addr
+	CIDRVal(path string, o ...Opts) netip.Prefix
+	URLArray(path string, o ...Opts) []*url.URL
+	HostPortArray(path string, o ...Opts) []HostPort
+	IPArray(path string, o ...Opts) []netip.Addr
+	CIDRArray(path string, o ...Opts) []netip.Prefix
+	SecretVal(path string, o ...Opts) Secret
+
+	// Decode converts the value at the supplied path to the type of target, which must be a non-nil pointer. The target
+	// is not modified if the value cannot be converted.
+	Decode(path string, target interface{}, o ...Opts)
 }
 
 func NewDeferredErrorQuietSelector(conf Selector, errorFunc func(path string, err error)) QuietSelector {
@@ -87,6 +121,105 @@ func (dqs *DeferredErrorQuietSelector) IntVal(path string, o ...Opts) int {
 
 }
 
+func (dqs *DeferredErrorQuietSelector) Int64Val(path string, o ...Opts) int64 {
+
+	if v, err := dqs.conf.Int64Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Int32Val(path string, o ...Opts) int32 {
+
+	if v, err := dqs.conf.Int32Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Int16Val(path string, o ...Opts) int16 {
+
+	if v, err := dqs.conf.Int16Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Int8Val(path string, o ...Opts) int8 {
+
+	if v, err := dqs.conf.Int8Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) UintVal(path string, o ...Opts) uint {
+
+	if v, err := dqs.conf.UintVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Uint64Val(path string, o ...Opts) uint64 {
+
+	if v, err := dqs.conf.Uint64Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Uint32Val(path string, o ...Opts) uint32 {
+
+	if v, err := dqs.conf.Uint32Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Uint16Val(path string, o ...Opts) uint16 {
+
+	if v, err := dqs.conf.Uint16Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) Uint8Val(path string, o ...Opts) uint8 {
+
+	if v, err := dqs.conf.Uint8Val(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
 func (dqs *DeferredErrorQuietSelector) Float64Val(path string, o ...Opts) float64 {
 
 	if v, err := dqs.conf.Float64Val(path, o...); err != nil {
@@ -147,7 +280,168 @@ func (dqs *DeferredErrorQuietSelector) BoolVal(path string, o ...Opts) bool {
 
 }
 
+func (dqs *DeferredErrorQuietSelector) DurationVal(path string, o ...Opts) time.Duration {
+
+	if v, err := dqs.conf.DurationVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) TimeVal(path string, o ...Opts) time.Time {
+
+	if v, err := dqs.conf.TimeVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return time.Time{}
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) DurationArray(path string, o ...Opts) []time.Duration {
+	if v, err := dqs.conf.DurationArray(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (dqs *DeferredErrorQuietSelector) TimeArray(path string, o ...Opts) []time.Time {
+	if v, err := dqs.conf.TimeArray(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (dqs *DeferredErrorQuietSelector) ByteSizeVal(path string, o ...Opts) ByteSize {
+
+	if v, err := dqs.conf.ByteSizeVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) SecretVal(path string, o ...Opts) Secret {
+
+	if v, err := dqs.conf.SecretVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return Secret{}
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) QuantityVal(path string, o ...Opts) float64 {
+
+	if v, err := dqs.conf.QuantityVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return 0
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) URLVal(path string, o ...Opts) *url.URL {
+
+	if v, err := dqs.conf.URLVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) HostPortVal(path string, o ...Opts) HostPort {
+
+	if v, err := dqs.conf.HostPortVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return HostPort{}
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) IPVal(path string, o ...Opts) netip.Addr {
+
+	if v, err := dqs.conf.IPVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return netip.Addr{}
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) CIDRVal(path string, o ...Opts) netip.Prefix {
+
+	if v, err := dqs.conf.CIDRVal(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return netip.Prefix{}
+	} else {
+		return v
+	}
+
+}
+
+func (dqs *DeferredErrorQuietSelector) URLArray(path string, o ...Opts) []*url.URL {
+	if v, err := dqs.conf.URLArray(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (dqs *DeferredErrorQuietSelector) HostPortArray(path string, o ...Opts) []HostPort {
+	if v, err := dqs.conf.HostPortArray(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (dqs *DeferredErrorQuietSelector) IPArray(path string, o ...Opts) []netip.Addr {
+	if v, err := dqs.conf.IPArray(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (dqs *DeferredErrorQuietSelector) CIDRArray(path string, o ...Opts) []netip.Prefix {
+	if v, err := dqs.conf.CIDRArray(path, o...); err != nil {
+		dqs.handleError(path, err)
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (dqs *DeferredErrorQuietSelector) Decode(path string, target interface{}, o ...Opts) {
+
+	if err := dqs.conf.Decode(path, target, o...); err != nil {
+		dqs.handleError(path, err)
+	}
+
+}
+
 // QuietSelectorFromPathValues creates a new QuietSelector populated with a map of complete paths (e.g. "my.config.path": "value")
-func QuietSelectorFromPathValues(pv map[string]interface{}, errorFunc func(path string, err error)) QuietSelector {
-	return NewDeferredErrorQuietSelector(SelectorFromPathValues(pv), errorFunc)
+func QuietSelectorFromPathValues(pv map[string]interface{}, errorFunc func(path string, err error), o ...SelectorOpts) QuietSelector {
+	return NewDeferredErrorQuietSelector(SelectorFromPathValues(pv, o...), errorFunc)
 }
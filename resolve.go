@@ -0,0 +1,221 @@
+package config_access
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Resolver returns the value referred to by a reference, which is the part of a string after the scheme and colon
+// (e.g. /run/secrets/db_password in file:/run/secrets/db_password).
+type Resolver func(reference string) (string, error)
+
+// Resolvers is a registry of Resolvers keyed by scheme. When a Selector or call has Resolvers, a string value that
+// starts with a registered scheme followed by a colon (e.g. file:/run/secrets/db_password) is replaced with the result
+// of the scheme's Resolver. Strings that do not start with a registered scheme (e.g. https://example.com) are unchanged.
+type Resolvers struct {
+	byScheme map[string]Resolver
+}
+
+// NewResolvers creates an empty resolver registry
+func NewResolvers() *Resolvers {
+	return &Resolvers{byScheme: make(map[string]Resolver)}
+}
+
+// DefaultResolvers creates a resolver registry with the file, env and base64 schemes registered, using the
+// FileResolver, EnvResolver and Base64Resolver that read from the real file system and environment.
+func DefaultResolvers() *Resolvers {
+
+	r := NewResolvers()
+
+	r.Register("file", FileResolver(nil))
+	r.Register("env", EnvResolver(nil))
+	r.Register("base64", Base64Resolver)
+
+	return r
+}
+
+// Register adds a Resolver for the supplied scheme (without the trailing colon), replacing any existing Resolver for
+// that scheme. Schemes are case-sensitive.
+func (r *Resolvers) Register(scheme string, f Resolver) {
+	r.byScheme[scheme] = f
+}
+
+// Schemes returns the registered schemes in alphabetical order
+func (r *Resolvers) Schemes() []string {
+
+	schemes := make([]string, 0, len(r.byScheme))
+
+	for s := range r.byScheme {
+		schemes = append(schemes, s)
+	}
+
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+func (r *Resolvers) resolver(scheme string) (Resolver, bool) {
+
+	if r == nil {
+		return nil, false
+	}
+
+	f, found := r.byScheme[scheme]
+
+	return f, found
+}
+
+// FileResolver creates a Resolver that returns the contents of the file named by the reference, without any trailing
+// line break (as is usual for secrets mounted as files). readFile is used to read the file; if it is nil, os.ReadFile
+// is used.
+func FileResolver(readFile func(name string) ([]byte, error)) Resolver {
+
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+
+	return func(reference string) (string, error) {
+
+		b, err := readFile(reference)
+
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+}
+
+// EnvResolver creates a Resolver that returns the value of the environment variable named by the reference. It is an
+// error for the variable to be unset or empty. getEnv is used to read the variable; if it is nil, os.Getenv is used.
+func EnvResolver(getEnv func(string) string) Resolver {
+
+	if getEnv == nil {
+		getEnv = os.Getenv
+	}
+
+	return func(reference string) (string, error) {
+
+		if v := getEnv(reference); v != "" {
+			return v, nil
+		}
+
+		return "", fmt.Errorf("environment variable %s is not set", reference)
+	}
+}
+
+// Base64Resolver decodes the reference as standard base64, with or without padding
+func Base64Resolver(reference string) (string, error) {
+
+	b, err := base64.StdEncoding.DecodeString(reference)
+
+	if err != nil {
+		if b, err = base64.RawStdEncoding.DecodeString(reference); err != nil {
+			return "", err
+		}
+	}
+
+	return string(b), nil
+}
+
+// resolve replaces a string of the form scheme:reference with the result of the first registered Resolver for the scheme
+func (c conversion) resolve(path, s string) (string, error) {
+
+	scheme, reference, found := strings.Cut(s, ":")
+
+	if !found {
+		return s, nil
+	}
+
+	for _, rs := range c.resolvers {
+
+		if f, found := rs.resolver(scheme); found {
+
+			v, err := f(reference)
+
+			if err != nil {
+				return "", ResolverError{Path: path, Scheme: scheme, Err: err}
+			}
+
+			return v, nil
+		}
+	}
+
+	return s, nil
+}
+
+// expandString expands any ${...} references in a string and then resolves it if it starts with a registered scheme
+func (c conversion) expandString(path, s string) (string, error) {
+
+	var err error
+
+	if c.interpolator != nil {
+		if s, err = c.interpolator.expand(path, s, []string{path}); err != nil {
+			return "", err
+		}
+	}
+
+	if len(c.resolvers) > 0 {
+		return c.resolve(path, s)
+	}
+
+	return s, nil
+}
+
+// expandsStrings returns true if strings are changed by expandString
+func (c conversion) expandsStrings() bool {
+	return c.interpolator != nil || len(c.resolvers) > 0
+}
+
+// expandAll returns a copy of the supplied value in which every string has been expanded with expandString
+func (c conversion) expandAll(path string, v interface{}) (interface{}, error) {
+
+	if o, found := object(v); found {
+
+		expanded := make(ConfigNode, len(o))
+
+		for k, m := range o {
+
+			mp := quoteKey(k, c.separator)
+
+			if path != "" {
+				mp = c.memberPath(path, k)
+			}
+
+			em, err := c.expandAll(mp, m)
+
+			if err != nil {
+				return nil, err
+			}
+
+			expanded[k] = em
+		}
+
+		return expanded, nil
+	}
+
+	switch t := v.(type) {
+	case []interface{}:
+		expanded := make([]interface{}, len(t))
+
+		for i, e := range t {
+
+			ee, err := c.expandAll(elementPath(path, i), e)
+
+			if err != nil {
+				return nil, err
+			}
+
+			expanded[i] = ee
+		}
+
+		return expanded, nil
+	case string:
+		return c.expandString(path, t)
+	}
+
+	return v, nil
+}
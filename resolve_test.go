@@ -0,0 +1,205 @@
+package config_access_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeResolvers() *ca.Resolvers {
+
+	files := map[string]string{
+		"/run/secrets/db_password": "pa55word\n",
+		"/run/secrets/prod/key":    "prod-key",
+	}
+
+	r := ca.NewResolvers()
+
+	r.Register("file", ca.FileResolver(func(name string) ([]byte, error) {
+		if f, found := files[name]; found {
+			return []byte(f), nil
+		}
+
+		return nil, fs.ErrNotExist
+	}))
+
+	r.Register("env", ca.EnvResolver(func(name string) string {
+		return map[string]string{"DB_USER": "app"}[name]
+	}))
+
+	r.Register("base64", ca.Base64Resolver)
+
+	return r
+}
+
+func TestResolversOnSelector(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "secrets.json")
+	yamlConf := loadYamlTestFile(t, "secrets.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Resolvers: fakeResolvers(), Interpolate: true})
+
+		s, err := cs.StringVal("database.user")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "app", s)
+
+		s, err = cs.StringVal("database.password")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "pa55word", s)
+
+		s, err = cs.StringVal("database.certificate")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "-----BEGIN-----", s)
+
+		// References are expanded before they are resolved
+		s, err = cs.StringVal("perEnvironment")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "prod-key", s)
+
+		// Unregistered schemes are left alone
+		s, _ = cs.StringVal("database.url")
+		assert.EqualValues(t, "https://example.com/db", s)
+
+		s, _ = cs.StringVal("database.token")
+		assert.EqualValues(t, "vault:secret/data/api#token", s)
+
+		u, err := cs.URLVal("database.url")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "example.com", u.Host)
+	}
+}
+
+func TestResolversPerCall(t *testing.T) {
+
+	node := loadJsonTestFile(t, "secrets.json")
+
+	vault := ca.NewResolvers()
+	vault.Register("vault", func(reference string) (string, error) {
+		return "token-for-" + reference, nil
+	})
+	vault.Register("env", func(reference string) (string, error) {
+		return "overridden", nil
+	})
+
+	cs := ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Resolvers: fakeResolvers()})
+
+	s, err := cs.StringVal("database.token", ca.Opts{Resolvers: vault})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "token-for-secret/data/api#token", s)
+
+	// Resolvers supplied for a call take precedence
+	s, _ = cs.StringVal("database.user", ca.Opts{Resolvers: vault})
+	assert.EqualValues(t, "overridden", s)
+
+	s, _ = cs.StringVal("database.password", ca.Opts{Resolvers: vault})
+	assert.EqualValues(t, "pa55word", s)
+
+	// Without resolvers, values are returned as written
+	plain := ca.NewDefaultSelector(node, true, true)
+
+	s, _ = plain.StringVal("database.password")
+	assert.EqualValues(t, "file:/run/secrets/db_password", s)
+
+	s, _ = plain.StringVal("database.password", ca.Opts{Resolvers: fakeResolvers()})
+	assert.EqualValues(t, "pa55word", s)
+
+	assert.EqualValues(t, []string{"env", "vault"}, vault.Schemes())
+}
+
+func TestResolverErrors(t *testing.T) {
+
+	node := loadYamlTestFile(t, "secrets.yaml")
+
+	cs := ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Resolvers: fakeResolvers()})
+
+	var re ca.ResolverError
+
+	_, err := cs.StringVal("unreadable")
+	assert.True(t, errors.As(err, &re))
+	assert.EqualValues(t, "unreadable", re.Path)
+	assert.EqualValues(t, "file", re.Scheme)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = cs.StringVal("badEncoding")
+	assert.True(t, errors.As(err, &re))
+	assert.EqualValues(t, "base64", re.Scheme)
+
+	node["database"].(ca.ConfigNode)["user"] = "env:UNSET"
+
+	_, err = cs.StringVal("database.user")
+	assert.EqualValues(t, "unable to resolve env: reference in value at database.user: environment variable UNSET is not set", err.Error())
+}
+
+func TestPopulateWithResolvers(t *testing.T) {
+
+	type Database struct {
+		Host        string
+		User        string
+		Password    string
+		Certificate string
+	}
+
+	node := loadJsonTestFile(t, "secrets.json")
+
+	var db Database
+
+	err := ca.Populate("database", &db, node, ca.Opts{Resolvers: fakeResolvers()})
+	assert.Nil(t, err)
+	assert.EqualValues(t, Database{Host: "db.internal", User: "app", Password: "pa55word", Certificate: "-----BEGIN-----"}, db)
+
+	type Root struct {
+		PerEnvironment string
+		Database       Database
+	}
+
+	var r Root
+
+	// Members that do not populate a field (such as unreadable) are not resolved
+	err = ca.PopulateFromRoot(&r, node, ca.Opts{Resolvers: fakeResolvers(), Interpolate: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "prod-key", r.PerEnvironment)
+	assert.EqualValues(t, "pa55word", r.Database.Password)
+
+	node["database"].(ca.ConfigNode)["password"] = "file:/run/secrets/missing"
+
+	var re ca.ResolverError
+
+	err = ca.Populate("database", &db, node, ca.Opts{Resolvers: fakeResolvers()})
+	assert.True(t, errors.As(err, &re))
+	assert.EqualValues(t, "database.password", re.Path)
+}
+
+func TestResolveWithResolvers(t *testing.T) {
+
+	node := loadJsonTestFile(t, "secrets.json")
+
+	delete(node, "unreadable")
+	delete(node, "badEncoding")
+
+	resolved, err := ca.Resolve(node, ca.Opts{Resolvers: fakeResolvers()})
+	assert.Nil(t, err)
+
+	cs := ca.NewDefaultSelector(resolved, true, true)
+
+	assert.EqualValues(t, "pa55word", cs.Value("database.password"))
+	assert.EqualValues(t, "prod-key", cs.Value("perEnvironment"))
+
+	_, err = ca.Resolve(nil)
+	assert.True(t, errors.Is(err, ca.ErrNilConfig))
+}
+
+func TestBase64Resolver(t *testing.T) {
+
+	s, err := ca.Base64Resolver("aGVsbG8=")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "hello", s)
+
+	s, err = ca.Base64Resolver("aGVsbG8")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "hello", s)
+}
@@ -0,0 +1,214 @@
+package config_access
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Redacted is written in place of sensitive values
+const Redacted = "[REDACTED]"
+
+// Secret holds a sensitive string, such as a password or token. A Secret redacts itself when it is formatted with the
+// fmt package, converted to a string or marshalled as JSON or text, so it can be logged or dumped safely. Use Reveal to
+// recover the value.
+type Secret struct {
+	value string
+}
+
+// NewSecret creates a Secret holding the supplied value
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Reveal returns the value of the secret
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+// String returns Redacted
+func (s Secret) String() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter so that every verb (including %#v and %x) writes Redacted
+func (s Secret) Format(f fmt.State, verb rune) {
+
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", Redacted)
+		return
+	}
+
+	io.WriteString(f, Redacted)
+}
+
+// MarshalJSON implements json.Marshaler, writing Redacted as a JSON string
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler, writing Redacted
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// SecretVal returns the string at the supplied path as a Secret
+func SecretVal(path string, node ConfigNode, o ...Opts) (Secret, error) {
+	return decodeAs[Secret](defaultAccessor, path, node, options(o))
+}
+
+func convertSecret(c conversion, path string, v interface{}) (interface{}, error) {
+
+	if c.coerce {
+
+		var err error
+
+		if v, err = coerce(path, v, stringType); err != nil {
+			return nil, err
+		}
+	}
+
+	if s, found := v.(string); found {
+		return NewSecret(s), nil
+	}
+
+	return nil, TypeMismatchError{Path: path, Expected: "secret", Actual: kindOf(v)}
+}
+
+// DefaultSensitiveKeys are the key patterns used by Redact and Dump if no Sensitive is supplied
+var DefaultSensitiveKeys = []string{"*password*", "*passwd*", "*secret*", "*token*", "*apikey*", "*api_key*", "*credential*", "*private*key*"}
+
+// Sensitive identifies the values in configuration that must not be revealed by Redact and Dump
+type Sensitive struct {
+	// Paths or queries (as supported by Select, e.g. services.*.password) of sensitive values
+	Paths []string
+	// Patterns (as supported by path.Match, e.g. *password*) that are matched case-insensitively against keys. The
+	// values of matching keys, including whole objects and arrays, are sensitive.
+	Keys []string
+}
+
+// Redact returns a copy of the supplied config in which sensitive values are replaced with Redacted. If no Sensitive
+// is supplied, keys matching DefaultSensitiveKeys are redacted. An error is returned if a path or pattern is invalid.
+//
+// Secret values in the config are always redacted.
+func Redact(node ConfigNode, s ...Sensitive) (ConfigNode, error) {
+
+	if node == nil {
+		return nil, ErrNilConfig
+	}
+
+	if len(s) == 0 {
+		s = []Sensitive{{Keys: DefaultSensitiveKeys}}
+	}
+
+	paths := make(map[string]bool)
+	var keys []string
+
+	for _, sensitive := range s {
+
+		for _, query := range sensitive.Paths {
+
+			matches, err := Select(query, node)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, m := range matches {
+				paths[m.Path] = true
+			}
+		}
+
+		for _, k := range sensitive.Keys {
+
+			k = strings.ToLower(k)
+
+			if _, err := path.Match(k, ""); err != nil {
+				return nil, fmt.Errorf("invalid sensitive key pattern %q: %w", k, err)
+			}
+
+			keys = append(keys, k)
+		}
+	}
+
+	r := redactor{paths: paths, keys: keys}
+
+	return r.redact(node, nil).(ConfigNode), nil
+}
+
+// Dump returns the supplied config as indented JSON, with sensitive values redacted as described in Redact. Use it
+// when logging or displaying configuration.
+func Dump(node ConfigNode, s ...Sensitive) (string, error) {
+
+	redacted, err := Redact(node, s...)
+
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(redacted, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+type redactor struct {
+	paths map[string]bool
+	keys  []string
+}
+
+func (r redactor) redact(v interface{}, elements []interface{}) interface{} {
+
+	if len(elements) > 0 && r.paths[BuildPath(elements...)] {
+		return Redacted
+	}
+
+	if o, found := object(v); found {
+
+		redacted := make(ConfigNode, len(o))
+
+		for k, m := range o {
+
+			if r.sensitiveKey(k) {
+				redacted[k] = Redacted
+			} else {
+				redacted[k] = r.redact(m, append(elements[:len(elements):len(elements)], k))
+			}
+		}
+
+		return redacted
+	}
+
+	switch t := v.(type) {
+	case []interface{}:
+		redacted := make([]interface{}, len(t))
+
+		for i, e := range t {
+			redacted[i] = r.redact(e, append(elements[:len(elements):len(elements)], i))
+		}
+
+		return redacted
+	case Secret, *Secret:
+		return Redacted
+	}
+
+	return v
+}
+
+func (r redactor) sensitiveKey(key string) bool {
+
+	key = strings.ToLower(key)
+
+	for _, k := range r.keys {
+		if matched, _ := path.Match(k, key); matched {
+			return true
+		}
+	}
+
+	return false
+}
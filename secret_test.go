@@ -0,0 +1,156 @@
+package config_access_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRedactsItself(t *testing.T) {
+
+	s := ca.NewSecret("pa55word")
+
+	assert.EqualValues(t, "pa55word", s.Reveal())
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%x", "%d"} {
+		assert.EqualValues(t, ca.Redacted, fmt.Sprintf(format, s))
+	}
+
+	assert.EqualValues(t, `"[REDACTED]"`, fmt.Sprintf("%q", s))
+	assert.EqualValues(t, ca.Redacted, s.String())
+
+	type Database struct {
+		User     string
+		Password ca.Secret
+	}
+
+	db := Database{User: "app", Password: s}
+
+	assert.NotContains(t, fmt.Sprintf("%+v", db), "pa55word")
+	assert.NotContains(t, fmt.Sprintf("%#v", &db), "pa55word")
+
+	b, err := json.Marshal(db)
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"User":"app","Password":"[REDACTED]"}`, string(b))
+}
+
+func TestSecretVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "secrets.json")
+	yamlConf := loadYamlTestFile(t, "secrets.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		s, err := ca.SecretVal("database.host", node)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "db.internal", s.Reveal())
+
+		_, err = ca.SecretVal("database", node)
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "secret", tme.Expected)
+
+		cs := ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Resolvers: fakeResolvers()})
+
+		s, err = cs.SecretVal("database.password")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "pa55word", s.Reveal())
+
+		s, err = ca.SecretVal("database.password", node, ca.Opts{Resolvers: fakeResolvers()})
+		assert.Nil(t, err)
+		assert.EqualValues(t, "pa55word", s.Reveal())
+
+		qs := ca.NewDeferredErrorQuietSelector(cs, func(path string, err error) {})
+		assert.EqualValues(t, "app", qs.SecretVal("database.user").Reveal())
+	}
+}
+
+func TestSecretFields(t *testing.T) {
+
+	type Database struct {
+		Host     string
+		Password ca.Secret
+		Tokens   []ca.Secret `json:"tokens"`
+	}
+
+	node := ca.ConfigNode{"database": ca.ConfigNode{"host": "db.internal", "password": "pa55word", "tokens": []interface{}{"a", "b"}}}
+
+	var db Database
+
+	assert.Nil(t, ca.Populate("database", &db, node))
+	assert.EqualValues(t, "db.internal", db.Host)
+	assert.EqualValues(t, "pa55word", db.Password.Reveal())
+	assert.Len(t, db.Tokens, 2)
+	assert.EqualValues(t, "b", db.Tokens[1].Reveal())
+
+	db = Database{}
+
+	assert.Nil(t, ca.SetField("Password", "database.password", &db, node))
+	assert.EqualValues(t, "pa55word", db.Password.Reveal())
+
+	s, err := ca.Get[ca.Secret](ca.NewDefaultSelector(node, true, true), "database.password")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "pa55word", s.Reveal())
+}
+
+func TestRedact(t *testing.T) {
+
+	node := ca.ConfigNode{
+		"database": ca.ConfigNode{
+			"host":        "db.internal",
+			"password":    "pa55word",
+			"credentials": ca.ConfigNode{"user": "app", "key": "k"},
+		},
+		"api":      ca.ConfigNode{"API_TOKEN": "t0ken", "url": "https://example.com"},
+		"services": []interface{}{ca.ConfigNode{"name": "a", "pin": "1234"}, ca.ConfigNode{"name": "b", "pin": "5678"}},
+		"signing":  ca.NewSecret("s1gn"),
+	}
+
+	redacted, err := ca.Redact(node)
+	assert.Nil(t, err)
+
+	cs := ca.NewDefaultSelector(redacted, true, true)
+
+	assert.EqualValues(t, "db.internal", cs.Value("database.host"))
+	assert.EqualValues(t, ca.Redacted, cs.Value("database.password"))
+	assert.EqualValues(t, ca.Redacted, cs.Value("database.credentials"))
+	assert.EqualValues(t, ca.Redacted, cs.Value("api.API_TOKEN"))
+	assert.EqualValues(t, ca.Redacted, cs.Value("signing"))
+	assert.EqualValues(t, "1234", cs.Value("services[0].pin"))
+
+	// The original is unchanged
+	assert.EqualValues(t, "pa55word", ca.Value("database.password", node))
+
+	redacted, err = ca.Redact(node, ca.Sensitive{Paths: []string{"services.*.pin"}, Keys: []string{"host"}})
+	assert.Nil(t, err)
+
+	cs = ca.NewDefaultSelector(redacted, true, true)
+
+	assert.EqualValues(t, ca.Redacted, cs.Value("services[1].pin"))
+	assert.EqualValues(t, "b", cs.Value("services[1].name"))
+	assert.EqualValues(t, ca.Redacted, cs.Value("database.host"))
+	assert.EqualValues(t, "pa55word", cs.Value("database.password"))
+
+	_, err = ca.Redact(node, ca.Sensitive{Keys: []string{"["}})
+	assert.NotNil(t, err)
+
+	_, err = ca.Redact(node, ca.Sensitive{Paths: []string{"services[?"}})
+	assert.NotNil(t, err)
+
+	_, err = ca.Redact(nil)
+	assert.True(t, errors.Is(err, ca.ErrNilConfig))
+}
+
+func TestDump(t *testing.T) {
+
+	node := ca.ConfigNode{"database": ca.ConfigNode{"host": "db.internal", "password": "pa55word"}}
+
+	d, err := ca.Dump(node)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "{\n  \"database\": {\n    \"host\": \"db.internal\",\n    \"password\": \"[REDACTED]\"\n  }\n}", d)
+}
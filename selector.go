@@ -4,25 +4,18 @@
 package config_access
 
 import (
-	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"time"
 )
 
 const PathSeparator = "."
 
 type ConfigNode = map[string]interface{}
 
-// MissingPathError indicates that the a problem was caused by there being no value at the supplied
-// config path
-type MissingPathError struct {
-	message string
-}
-
-func (mp MissingPathError) Error() string {
-	return mp.message
-}
-
 type Selector interface {
 	PathExists(path string) bool
 	Value(path string, o ...Opts) interface{}
@@ -36,44 +29,173 @@ type Selector interface {
 	// variable can both be overridden in the Opts argument.
 	StringOrEnv(path string, o ...Opts) (string, error)
 	IntVal(path string, o ...Opts) (int, error)
+
+	// Int64Val, Int32Val, Int16Val, Int8Val, UintVal, Uint64Val, Uint32Val, Uint16Val and Uint8Val return the number at
+	// the supplied path as the relevant integer type. An error is returned if the number is not a whole number or is
+	// outside the range of the type.
+	Int64Val(path string, o ...Opts) (int64, error)
+	Int32Val(path string, o ...Opts) (int32, error)
+	Int16Val(path string, o ...Opts) (int16, error)
+	Int8Val(path string, o ...Opts) (int8, error)
+	UintVal(path string, o ...Opts) (uint, error)
+	Uint64Val(path string, o ...Opts) (uint64, error)
+	Uint32Val(path string, o ...Opts) (uint32, error)
+	Uint16Val(path string, o ...Opts) (uint16, error)
+	Uint8Val(path string, o ...Opts) (uint8, error)
 	Float64Val(path string, o ...Opts) (float64, error)
 	Array(path string, o ...Opts) ([]interface{}, error)
 	StringArray(path string, o ...Opts) ([]string, error)
 	IntArray(path string, o ...Opts) ([]int, error)
 	Float64Array(path string, o ...Opts) ([]float64, error)
 	BoolVal(path string, o ...Opts) (bool, error)
+
+	// DurationVal returns the value at the supplied path as a time.Duration. See the DurationVal function for the
+	// formats that are supported.
+	DurationVal(path string, o ...Opts) (time.Duration, error)
+
+	// TimeVal returns the value at the supplied path as a time.Time. The value must be an RFC 3339 timestamp or match
+	// one of the layouts in Opts.TimeLayouts or SelectorOpts.TimeLayouts.
+	TimeVal(path string, o ...Opts) (time.Time, error)
+	DurationArray(path string, o ...Opts) ([]time.Duration, error)
+	TimeArray(path string, o ...Opts) ([]time.Time, error)
+
+	// ByteSizeVal returns the value at the supplied path as a number of bytes. See ParseByteSize for the formats that
+	// are supported.
+	ByteSizeVal(path string, o ...Opts) (ByteSize, error)
+
+	// QuantityVal returns the number at the supplied path or, if the value is a string, the result of parsing it with
+	// ParseQuantity.
+	QuantityVal(path string, o ...Opts) (float64, error)
+
+	// URLVal, HostPortVal, IPVal and CIDRVal return the string at the supplied path as the relevant network type. See
+	// the functions of the same name for the formats that are supported.
+	URLVal(path string, o ...Opts) (*url.URL, error)
+	HostPortVal(path string, o ...Opts) (HostPort, error)
+	IPVal(path string, o ...Opts) (netip.Addr, error)
+	CIDRVal(path string, o ...Opts) (netip.Prefix, error)
+	URLArray(path string, o ...Opts) ([]*url.URL, error)
+	HostPortArray(path string, o ...Opts) ([]HostPort, error)
+	IPArray(path string, o ...Opts) ([]netip.Addr, error)
+	CIDRArray(path string, o ...Opts) ([]netip.Prefix, error)
+
+	// SecretVal returns the string at the supplied path as a Secret, which redacts itself when it is logged or printed
+	SecretVal(path string, o ...Opts) (Secret, error)
+
+	// Decode converts the value at the supplied path to the type of target, which must be a non-nil pointer. See Get
+	// for the types that are supported.
+	Decode(path string, target interface{}, o ...Opts) error
+
+	// Select returns every value that matches the supplied query, which may contain wildcards (services.*.port) and
+	// recursive descents (http..timeout), along with the concrete path of each value. See the Select function for details.
+	Select(query string) ([]PathValue, error)
+
+	// Query evaluates a query expression, which may include filters such as databases[?role=='primary'].host, and
+	// returns the matched values. See the Query function for details.
+	Query(expression string) (QueryResult, error)
 	Flush()
 	Config() ConfigNode
 }
 
 // Opts defines optional behaviour for accessing and interpreting config values
 type Opts struct {
-	// If this value is set, it will be returned instead of an error if there is no value at the requested path
+	// If this value is set, it will be returned instead of an error if there is no value at the requested path. An error
+	// is returned if the value is not of the requested type.
 	OnMissing any
 	// This function will be used instead of os.GetEnv by functions that read environment variables
 	EnvAccessFunc func(string) string
 	// If set this string is the prefix that is used to indicate that a value is the name of an environment variable (default is $)
 	EnvVarPrefix string
+	// Converters used by Decode and Get in preference to any registered with the Selector
+	Converters *Converters
+	// Layouts (as used by time.Parse) that are tried if a time is not in RFC 3339 format
+	TimeLayouts []string
+	// Convert between strings, numbers and bools for this access. See SelectorOpts.Coerce
+	Coerce bool
+	// Expand references in strings for this access. See SelectorOpts.Interpolate
+	Interpolate bool
+	// Resolvers used in preference to any registered with the Selector. See SelectorOpts.Resolvers
+	Resolvers *Resolvers
+	// When populating a struct (with Populate, PopulateFromRoot, Get or Decode), report every key in the config that
+	// does not map to a field as an UnknownKeyError
+	Strict bool
+}
+
+// SelectorOpts defines optional behaviour for a Selector
+type SelectorOpts struct {
+	// The string used to separate the elements of a path (e.g. "/" or ":"). If not set, PathSeparator is used. The
+	// separator must not contain the characters [ ] " or \ as they have a special meaning in paths. Functions that
+	// create a Selector and return an error (e.g. Loader.Selector and LoadDir) return an InvalidSeparatorError if it
+	// does, while NewDefaultSelector, NewGraniticSelector and SelectorFromPathValues panic. Use Validate to check
+	// options that are not known to be valid before passing them to those functions.
+	Separator string
+	// Converters used by Decode and Get to convert values to types that are not supported by default
+	Converters *Converters
+	// Layouts (as used by time.Parse) that are tried if a time is not in RFC 3339 format
+	TimeLayouts []string
+	// If true, values are converted between strings, numbers and bools when the requested type does not match the
+	// value in the configuration (by default this is an error). Numbers and bools are converted to strings in their
+	// shortest form (32, 32.5, true). Strings are converted to numbers if they can be parsed as a number and to bools
+	// if they are one of the YAML 1.1 booleans (y, yes, true, on, n, no, false, off; case-insensitive) or 1 or 0.
+	// Numbers are converted to bools if they are 1 or 0 and bools to numbers as 1 or 0. Numeric strings are also
+	// accepted as durations in milliseconds.
+	Coerce bool
+	// If true, references of the form ${name} in strings are expanded when they are read, as described in Interpolate.
+	// Names are resolved against the Selector's config and EnvAccessFunc in Opts is used to read environment variables.
+	// Expansion applies to StringVal, StringOrEnv, StringArray and to strings converted by Decode, Get and the accessors
+	// for durations, times, sizes and network types, but not to the values returned by Value, ObjectVal or Array.
+	// Combine with Coerce to use an expanded string as a number or bool.
+	Interpolate bool
+	// If set, strings of the form scheme:reference (e.g. file:/run/secrets/db_password) whose scheme is registered are
+	// replaced with the result of the scheme's Resolver when they are read. Resolution applies to the same accessors as
+	// Interpolate and happens after references have been expanded.
+	Resolvers *Resolvers
+}
+
+// Validate returns an InvalidSeparatorError if the options cannot be used to create a Selector
+func (so SelectorOpts) Validate() error {
+	return validSeparator(so.Separator)
 }
 
 // SelectorFromPathValues creates a Selector from a map of config paths (e.g. my.config.path) and their
-// associated values. Empty path values are ignored.
-func SelectorFromPathValues(pathValues map[string]interface{}) Selector {
+// associated values. Keys containing the separator can be quoted or escaped as described in Value. Empty paths and
+// paths that cannot be parsed or that contain array indexes are ignored.
+//
+// If a separator is set in the optional SelectorOpts, it is used both to split the supplied paths and by the returned Selector.
+func SelectorFromPathValues(pathValues map[string]interface{}, o ...SelectorOpts) Selector {
+
+	opts := selectorOptions(o)
+
+	return NewDefaultSelector(nodeFromPathValues(pathValues, newAccessor(opts).separator()), true, true, opts)
+
+}
+
+func nodeFromPathValues(pathValues map[string]interface{}, separator string) ConfigNode {
 
 	store := make(map[string]interface{})
 
-	for k, v := range pathValues {
+	// Paths are added in order so that, if a path is a prefix of another path (e.g. a.b and a.b.c), the longer path
+	// consistently replaces the value of the shorter path with an object
+	paths := make([]string, 0, len(pathValues))
+
+	for k := range pathValues {
+		paths = append(paths, k)
+	}
+
+	sort.Strings(paths)
+
+	for _, k := range paths {
 
 		if strings.TrimSpace(k) == "" {
 			continue
 		}
 
-		addValue(strings.Split(k, "."), v, store)
+		if keys, err := pathKeys(k, separator); err == nil {
+			addValue(keys, pathValues[k], store)
+		}
 
 	}
 
-	return NewDefaultSelector(store, true, true)
-
+	return store
 }
 
 func addValue(path []string, value any, store map[string]interface{}) {
@@ -84,32 +206,33 @@ func addValue(path []string, value any, store map[string]interface{}) {
 		store[first] = value
 	} else {
 
-		storeForFirst := store[first]
+		storeForFirst, found := store[first].(map[string]interface{})
 
-		if storeForFirst == nil {
-			newFirst := make(map[string]interface{})
-			store[first] = newFirst
-			addValue(path[1:], value, newFirst)
-		} else {
-			addValue(path[1:], value, storeForFirst.(map[string]interface{}))
+		if !found {
+			storeForFirst = make(map[string]interface{})
+			store[first] = storeForFirst
 		}
 
+		addValue(path[1:], value, storeForFirst)
+
 	}
 
 }
 
-func NewDefaultSelector(config ConfigNode, errorOnMissingObjectPath, errorOnMissingArrayPath bool) Selector {
+func NewDefaultSelector(config ConfigNode, errorOnMissingObjectPath, errorOnMissingArrayPath bool, o ...SelectorOpts) Selector {
 	ds := new(DefaultSelector)
 	ds.config = config
+	ds.access = newAccessor(selectorOptions(o))
 	ds.errorOnMissingArrayPath = errorOnMissingArrayPath
 	ds.errorOnMissingObjectPath = errorOnMissingObjectPath
 
 	return ds
 }
 
-func NewGraniticSelector(config ConfigNode) Selector {
+func NewGraniticSelector(config ConfigNode, o ...SelectorOpts) Selector {
 	ds := new(DefaultSelector)
 	ds.config = config
+	ds.access = newAccessor(selectorOptions(o))
 
 	return ds
 }
@@ -118,6 +241,7 @@ type DefaultSelector struct {
 	errorOnMissingObjectPath bool
 	errorOnMissingArrayPath  bool
 	config                   ConfigNode
+	access                   accessor
 }
 
 func (dfe *DefaultSelector) Flush() {
@@ -125,11 +249,11 @@ func (dfe *DefaultSelector) Flush() {
 }
 
 func (dfe *DefaultSelector) PathExists(path string) bool {
-	return PathExists(path, dfe.config)
+	return dfe.access.pathExists(path, dfe.config)
 }
 
 func (dfe *DefaultSelector) Value(path string, o ...Opts) interface{} {
-	if v := Value(path, dfe.config); v != nil {
+	if v := dfe.access.value(path, dfe.config); v != nil {
 		return v
 	} else {
 		opts := options(o)
@@ -141,22 +265,22 @@ func (dfe *DefaultSelector) ObjectVal(path string, o ...Opts) (ConfigNode, error
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.(ConfigNode), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[ConfigNode](path, opts.OnMissing)
 	}
 
-	return ObjectVal(path, dfe.config, dfe.errorOnMissingObjectPath)
+	return dfe.access.objectVal(path, dfe.config, dfe.errorOnMissingObjectPath)
 }
 
 func (dfe *DefaultSelector) StringVal(path string, o ...Opts) (string, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.(string), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[string](path, opts.OnMissing)
 	}
 
-	return StringVal(path, dfe.config)
+	return dfe.access.withOpts(opts).stringVal(path, dfe.config)
 }
 
 func (dfe *DefaultSelector) StringOrEnv(path string, o ...Opts) (string, error) {
@@ -188,7 +312,7 @@ func (dfe *DefaultSelector) StringOrEnv(path string, o ...Opts) (string, error)
 		}
 
 		if value := getEnv(varName); value == "" {
-			return "", fmt.Errorf("Environment variable %s is not set", varName)
+			return "", EnvVarUnsetError{Path: path, Name: varName}
 		} else {
 			return value, nil
 		}
@@ -203,83 +327,199 @@ func (dfe *DefaultSelector) IntVal(path string, o ...Opts) (int, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.(int), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[int](path, opts.OnMissing)
 	}
 
-	return IntVal(path, dfe.config)
+	return dfe.access.withOpts(opts).intVal(path, dfe.config)
+}
+
+func (dfe *DefaultSelector) Int64Val(path string, o ...Opts) (int64, error) {
+	return decodeAs[int64](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Int32Val(path string, o ...Opts) (int32, error) {
+	return decodeAs[int32](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Int16Val(path string, o ...Opts) (int16, error) {
+	return decodeAs[int16](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Int8Val(path string, o ...Opts) (int8, error) {
+	return decodeAs[int8](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) UintVal(path string, o ...Opts) (uint, error) {
+	return decodeAs[uint](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Uint64Val(path string, o ...Opts) (uint64, error) {
+	return decodeAs[uint64](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Uint32Val(path string, o ...Opts) (uint32, error) {
+	return decodeAs[uint32](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Uint16Val(path string, o ...Opts) (uint16, error) {
+	return decodeAs[uint16](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Uint8Val(path string, o ...Opts) (uint8, error) {
+	return decodeAs[uint8](dfe.access, path, dfe.config, options(o))
 }
 
 func (dfe *DefaultSelector) Float64Val(path string, o ...Opts) (float64, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.(float64), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[float64](path, opts.OnMissing)
 	}
 
-	return Float64Val(path, dfe.config)
+	return dfe.access.withOpts(opts).float64Val(path, dfe.config)
 }
 
 func (dfe *DefaultSelector) Array(path string, o ...Opts) ([]interface{}, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.([]interface{}), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[[]interface{}](path, opts.OnMissing)
 	}
 
-	return Array(path, dfe.config, dfe.errorOnMissingArrayPath)
+	return dfe.access.array(path, dfe.config, dfe.errorOnMissingArrayPath)
 }
 
 func (dfe *DefaultSelector) StringArray(path string, o ...Opts) ([]string, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.([]string), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[[]string](path, opts.OnMissing)
 	}
 
-	return StringArray(path, dfe.config)
+	return dfe.access.withOpts(opts).stringArray(path, dfe.config)
 }
 
 func (dfe *DefaultSelector) IntArray(path string, o ...Opts) ([]int, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.([]int), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[[]int](path, opts.OnMissing)
 	}
 
-	return IntArray(path, dfe.config)
+	return dfe.access.withOpts(opts).intArray(path, dfe.config)
 }
 
 func (dfe *DefaultSelector) Float64Array(path string, o ...Opts) ([]float64, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.([]float64), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[[]float64](path, opts.OnMissing)
 	}
 
-	return Float64Array(path, dfe.config)
+	return dfe.access.withOpts(opts).float64Array(path, dfe.config)
 }
 
 func (dfe *DefaultSelector) BoolVal(path string, o ...Opts) (bool, error) {
 
 	opts := options(o)
 
-	if opts.OnMissing != nil && !PathExists(path, dfe.config) {
-		return opts.OnMissing.(bool), nil
+	if opts.OnMissing != nil && !dfe.access.pathExists(path, dfe.config) {
+		return onMissing[bool](path, opts.OnMissing)
 	}
 
-	return BoolVal(path, dfe.config)
+	return dfe.access.withOpts(opts).boolVal(path, dfe.config)
+}
+
+func (dfe *DefaultSelector) DurationVal(path string, o ...Opts) (time.Duration, error) {
+	return decodeAs[time.Duration](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) TimeVal(path string, o ...Opts) (time.Time, error) {
+	return decodeAs[time.Time](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) DurationArray(path string, o ...Opts) ([]time.Duration, error) {
+	return decodeAs[[]time.Duration](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) TimeArray(path string, o ...Opts) ([]time.Time, error) {
+	return decodeAs[[]time.Time](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) ByteSizeVal(path string, o ...Opts) (ByteSize, error) {
+	return decodeAs[ByteSize](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) SecretVal(path string, o ...Opts) (Secret, error) {
+	return decodeAs[Secret](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) QuantityVal(path string, o ...Opts) (float64, error) {
+	return dfe.access.quantityVal(path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) URLVal(path string, o ...Opts) (*url.URL, error) {
+	return decodeAs[*url.URL](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) HostPortVal(path string, o ...Opts) (HostPort, error) {
+	return decodeAs[HostPort](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) IPVal(path string, o ...Opts) (netip.Addr, error) {
+	return decodeAs[netip.Addr](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) CIDRVal(path string, o ...Opts) (netip.Prefix, error) {
+	return decodeAs[netip.Prefix](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) URLArray(path string, o ...Opts) ([]*url.URL, error) {
+	return decodeAs[[]*url.URL](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) HostPortArray(path string, o ...Opts) ([]HostPort, error) {
+	return decodeAs[[]HostPort](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) IPArray(path string, o ...Opts) ([]netip.Addr, error) {
+	return decodeAs[[]netip.Addr](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) CIDRArray(path string, o ...Opts) ([]netip.Prefix, error) {
+	return decodeAs[[]netip.Prefix](dfe.access, path, dfe.config, options(o))
+}
+
+func (dfe *DefaultSelector) Decode(path string, target interface{}, o ...Opts) error {
+	return dfe.access.decode(path, dfe.config, target, options(o))
+}
+
+func (dfe *DefaultSelector) Select(query string) ([]PathValue, error) {
+	return dfe.access.selectPaths(query, dfe.config)
+}
+
+func (dfe *DefaultSelector) Query(expression string) (QueryResult, error) {
+	return dfe.access.query(expression, dfe.config)
 }
 
 func (dfe *DefaultSelector) Config() ConfigNode {
 	return dfe.config
 }
 
+func selectorOptions(o []SelectorOpts) SelectorOpts {
+	if len(o) == 0 {
+		return SelectorOpts{}
+	} else {
+		return o[0]
+	}
+}
+
 func options(o []Opts) Opts {
 	if len(o) == 0 {
 		return Opts{}
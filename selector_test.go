@@ -2,6 +2,7 @@ package config_access_test
 
 import (
 	"encoding/json"
+	"errors"
 	ca "github.com/graniticio/config-access"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -414,3 +415,153 @@ func TestStringOrEnv(t *testing.T) {
 	assert.Equal(t, "ENV_VALUE", ev)
 
 }
+
+func TestSelectorFromPathValuesWithQuotedKeys(t *testing.T) {
+
+	pv := map[string]interface{}{
+		`hosts."example.com".timeout`: 10,
+		`loggers.log\.level`:          "warn",
+		"bad.[0]":                     "ignored",
+	}
+
+	s := ca.SelectorFromPathValues(pv)
+
+	i, err := s.IntVal(ca.BuildPath("hosts", "example.com", "timeout"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, i)
+
+	v, err := s.StringVal(`loggers."log.level"`)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "warn", v)
+
+	assert.False(t, s.PathExists("bad"))
+}
+
+func TestCustomPathSeparator(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "dotted-keys.json")
+	yamlConf := loadYamlTestFile(t, "dotted-keys.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Separator: "/"})
+
+		i, err := cs.IntVal("hosts/example.com/timeout")
+		assert.NoError(t, err)
+		assert.EqualValues(t, 30, i)
+
+		s, err := cs.StringVal("hosts/example.com/aliases[0]")
+		assert.NoError(t, err)
+		assert.EqualValues(t, "www.example.com", s)
+
+		s, err = cs.StringVal("loggers/log.level")
+		assert.NoError(t, err)
+		assert.EqualValues(t, "debug", s)
+
+		assert.True(t, cs.PathExists("hosts/example.com/aliases/0"))
+		assert.False(t, cs.PathExists("hosts.example.com.timeout"))
+
+		o, err := cs.ObjectVal("hosts/example.com")
+		assert.NoError(t, err)
+		assert.NotNil(t, o)
+	}
+}
+
+func TestSelectorFromPathValuesWithCustomSeparator(t *testing.T) {
+
+	pv := map[string]interface{}{
+		"Logging:LogLevel:Default":        "Information",
+		"Logging:LogLevel:Microsoft.Host": "Warning",
+	}
+
+	s := ca.SelectorFromPathValues(pv, ca.SelectorOpts{Separator: ":"})
+
+	v, err := s.StringVal("Logging:LogLevel:Default")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "Information", v)
+
+	v, err = s.StringVal("Logging:LogLevel:Microsoft.Host")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "Warning", v)
+
+	o, err := s.ObjectVal("Logging:LogLevel")
+	assert.NoError(t, err)
+	assert.Len(t, o, 2)
+
+	var invoked bool
+
+	qs := ca.QuietSelectorFromPathValues(pv, func(path string, err error) {
+		invoked = true
+	}, ca.SelectorOpts{Separator: ":"})
+
+	assert.EqualValues(t, "Information", qs.StringVal("Logging:LogLevel:Default"))
+	assert.False(t, invoked)
+}
+
+func TestInvalidPathSeparator(t *testing.T) {
+
+	for _, sep := range []string{"[", "]", `"`, `\`, "a[b"} {
+
+		opts := ca.SelectorOpts{Separator: sep}
+
+		assert.Panics(t, func() { ca.NewDefaultSelector(ca.ConfigNode{}, true, true, opts) })
+		assert.Panics(t, func() { ca.NewGraniticSelector(ca.ConfigNode{}, opts) })
+		assert.Panics(t, func() { ca.SelectorFromPathValues(map[string]interface{}{}, opts) })
+	}
+
+	assert.NotPanics(t, func() { ca.NewDefaultSelector(ca.ConfigNode{}, true, true, ca.SelectorOpts{Separator: "::"}) })
+}
+
+func TestInvalidPathSeparatorErrors(t *testing.T) {
+
+	opts := ca.SelectorOpts{Separator: "["}
+
+	var ise ca.InvalidSeparatorError
+
+	assert.True(t, errors.As(opts.Validate(), &ise))
+	assert.EqualValues(t, "[", ise.Separator)
+	assert.Nil(t, ca.SelectorOpts{Separator: "/"}.Validate())
+
+	// Constructors that return errors report the separator rather than panicking
+	_, err := ca.NewLoader().Selector(opts)
+	assert.True(t, errors.As(err, &ise))
+
+	_, _, err = ca.LoadDir("testdata/fragments", ca.DirOpts{Selector: opts})
+	assert.True(t, errors.As(err, &ise))
+
+	var source ca.Source
+
+	assert.NotPanics(t, func() { source = ca.PathValueSource("values", map[string]interface{}{"a": 1}, opts) })
+
+	_, err = ca.NewLoader().Add(source).Load()
+	assert.True(t, errors.As(err, &ise))
+}
+
+func TestBuildPathWithSeparator(t *testing.T) {
+
+	p := ca.BuildPathWithSeparator("/", "service", "db/primary", "host.name", 0)
+	assert.EqualValues(t, `service/"db/primary"/host.name[0]`, p)
+	assert.EqualValues(t, `"a:b"`, ca.QuoteKeyWithSeparator("a:b", ":"))
+	assert.EqualValues(t, "a.b", ca.BuildPathWithSeparator("", "a", "b"))
+
+	node := ca.ConfigNode{"service": ca.ConfigNode{"db/primary": ca.ConfigNode{"host.name": []interface{}{"h"}}}}
+	cs := ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{Separator: "/"})
+
+	s, err := cs.StringVal(p)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "h", s)
+}
+
+func TestSelectorFromOverlappingPathValues(t *testing.T) {
+
+	pv := map[string]interface{}{
+		"a.b":   1,
+		"a.b.c": 2,
+	}
+
+	s := ca.SelectorFromPathValues(pv)
+
+	i, err := s.IntVal("a.b.c")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, i)
+}
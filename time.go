@@ -0,0 +1,191 @@
+package config_access
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationVal returns the time.Duration represented by the value at the supplied path. The value may be a Go
+// duration string (1m30s), an ISO-8601 duration (PT1M30S) or a whole number of milliseconds.
+func DurationVal(path string, node ConfigNode, o ...Opts) (time.Duration, error) {
+	return decodeAs[time.Duration](defaultAccessor, path, node, options(o))
+}
+
+// TimeVal returns the time.Time represented by the string at the supplied path. The string may be an RFC 3339 timestamp
+// or match one of the layouts in Opts.TimeLayouts.
+func TimeVal(path string, node ConfigNode, o ...Opts) (time.Time, error) {
+	return decodeAs[time.Time](defaultAccessor, path, node, options(o))
+}
+
+// DurationArray returns an array of time.Duration from the array at the supplied path. Each element is interpreted as
+// described in DurationVal.
+func DurationArray(path string, node ConfigNode, o ...Opts) ([]time.Duration, error) {
+	return decodeAs[[]time.Duration](defaultAccessor, path, node, options(o))
+}
+
+// TimeArray returns an array of time.Time from the array at the supplied path. Each element is interpreted as
+// described in TimeVal.
+func TimeArray(path string, node ConfigNode, o ...Opts) ([]time.Time, error) {
+	return decodeAs[[]time.Time](defaultAccessor, path, node, options(o))
+}
+
+func convertDuration(c conversion, path string, v interface{}) (interface{}, error) {
+
+	if s, found := v.(string); found && c.coerce {
+
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			// A number of milliseconds
+			v = json.Number(strings.TrimSpace(s))
+		}
+	}
+
+	switch t := v.(type) {
+	case string:
+		d, err := parseDuration(t)
+
+		if err != nil {
+			return nil, InvalidValueError{Path: path, Expected: "time.Duration", Err: err}
+		}
+
+		return d, nil
+	default:
+		f, found := numberValue(v)
+
+		if !found {
+			return nil, TypeMismatchError{Path: path, Expected: "time.Duration", Actual: kindOf(v)}
+		}
+
+		if f != math.Trunc(f) || math.Abs(f) > math.MaxInt64/float64(time.Millisecond) {
+			return nil, InvalidValueError{Path: path, Expected: "time.Duration", Err: fmt.Errorf("%v is not a whole number of milliseconds", f)}
+		}
+
+		return time.Duration(f) * time.Millisecond, nil
+	}
+}
+
+func convertTime(c conversion, path string, v interface{}) (interface{}, error) {
+
+	if t, found := v.(time.Time); found {
+		// Produced by YAML parsers for unquoted timestamps
+		return t, nil
+	}
+
+	s, found := v.(string)
+
+	if !found {
+		return nil, TypeMismatchError{Path: path, Expected: "time.Time", Actual: kindOf(v)}
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range c.timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return nil, InvalidValueError{Path: path, Expected: "time.Time", Err: fmt.Errorf("%q is not an RFC 3339 timestamp or in a configured layout", s)}
+}
+
+// parseDuration parses a Go duration string (e.g. 1h30m) or an ISO-8601 duration (e.g. PT1H30M)
+func parseDuration(s string) (time.Duration, error) {
+
+	trimmed := strings.TrimPrefix(s, "-")
+
+	if strings.HasPrefix(trimmed, "P") {
+
+		d, err := parseISODuration(trimmed[1:])
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q: %w", s, err)
+		}
+
+		if trimmed != s {
+			d = -d
+		}
+
+		return d, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// parseISODuration parses the part of an ISO-8601 duration that follows the P. Weeks, days, hours, minutes and seconds
+// are supported (days are treated as 24 hours). Years and months are rejected as they do not have a fixed length.
+func parseISODuration(s string) (time.Duration, error) {
+
+	if s == "" || s == "T" {
+		return 0, errors.New("no components")
+	}
+
+	var total float64
+	var inTime bool
+
+	for s != "" {
+
+		if s[0] == 'T' {
+
+			if inTime {
+				return 0, errors.New("more than one T")
+			}
+
+			inTime = true
+			s = s[1:]
+
+			if s == "" {
+				return 0, errors.New("no components after T")
+			}
+
+			continue
+		}
+
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+
+		if end <= 0 {
+			return 0, fmt.Errorf("expected a number at %q", s)
+		}
+
+		n, err := strconv.ParseFloat(strings.Replace(s[:end], ",", ".", 1), 64)
+
+		if err != nil {
+			return 0, err
+		}
+
+		var unit time.Duration
+
+		switch designator := s[end]; {
+		case designator == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case designator == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case designator == 'H' && inTime:
+			unit = time.Hour
+		case designator == 'M' && inTime:
+			unit = time.Minute
+		case designator == 'S' && inTime:
+			unit = time.Second
+		case designator == 'Y' || designator == 'M':
+			return 0, errors.New("years and months do not have a fixed duration")
+		default:
+			return 0, fmt.Errorf("unexpected designator %q", designator)
+		}
+
+		total += n * float64(unit)
+		s = s[end+1:]
+	}
+
+	if total > math.MaxInt64 {
+		return 0, errors.New("duration is too long")
+	}
+
+	return time.Duration(total), nil
+}
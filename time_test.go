@@ -0,0 +1,188 @@
+package config_access_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ca "github.com/graniticio/config-access"
+	"github.com/stretchr/testify/assert"
+)
+
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+	Idle  *time.Duration
+	List  []time.Duration
+}
+
+type Schedule struct {
+	Start time.Time
+	End   time.Time
+	Dates []time.Time
+}
+
+type Timings struct {
+	Timeouts Timeouts
+	Schedule Schedule
+}
+
+func TestDurationVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "timings.json")
+	yamlConf := loadYamlTestFile(t, "timings.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		expected := map[string]time.Duration{
+			"timeouts.read":     90 * time.Second,
+			"timeouts.write":    45 * time.Second,
+			"timeouts.idle":     2500 * time.Millisecond,
+			"timeouts.long":     26 * time.Hour,
+			"timeouts.weeks":    14 * 24 * time.Hour,
+			"timeouts.negative": -1500 * time.Millisecond,
+		}
+
+		for path, d := range expected {
+			v, err := cs.DurationVal(path)
+			assert.Nil(t, err, path)
+			assert.EqualValues(t, d, v, path)
+		}
+
+		l, err := cs.DurationArray("timeouts.list")
+		assert.Nil(t, err)
+		assert.EqualValues(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, l)
+
+		var ive ca.InvalidValueError
+
+		for _, path := range []string{"timeouts.invalid", "timeouts.fractional", "timeouts.months"} {
+			_, err = cs.DurationVal(path)
+			assert.True(t, errors.As(err, &ive), path)
+			assert.EqualValues(t, path, ive.Path)
+		}
+
+		_, err = cs.DurationVal("timeouts.flag")
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "time.Duration", tme.Expected)
+
+		_, err = cs.DurationArray("timeouts.badList")
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "timeouts.badList[1]", ive.Path)
+
+		d, err := cs.DurationVal("timeouts.missing", ca.Opts{OnMissing: time.Minute})
+		assert.Nil(t, err)
+		assert.EqualValues(t, time.Minute, d)
+
+		d, err = ca.DurationVal("timeouts.read", node)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 90*time.Second, d)
+	}
+}
+
+func TestTimeVal(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "timings.json")
+	yamlConf := loadYamlTestFile(t, "timings.yaml")
+
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	holiday := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		cs := ca.NewDefaultSelector(node, true, true)
+
+		s, err := cs.TimeVal("schedule.start")
+		assert.Nil(t, err)
+		assert.True(t, start.Equal(s))
+
+		e, err := cs.TimeVal("schedule.end")
+		assert.Nil(t, err)
+		assert.True(t, start.Add(6*time.Hour+30*time.Minute).Equal(e))
+
+		dates, err := cs.TimeArray("schedule.dates")
+		assert.Nil(t, err)
+		assert.Len(t, dates, 2)
+		assert.True(t, start.AddDate(0, 3, 0).Equal(dates[1]))
+
+		_, err = cs.TimeVal("schedule.holiday")
+
+		var ive ca.InvalidValueError
+		assert.True(t, errors.As(err, &ive))
+		assert.EqualValues(t, "schedule.holiday", ive.Path)
+
+		h, err := cs.TimeVal("schedule.holiday", ca.Opts{TimeLayouts: []string{"2006-01-02", "02/01/2006"}})
+		assert.Nil(t, err)
+		assert.True(t, holiday.Equal(h))
+
+		cs = ca.NewDefaultSelector(node, true, true, ca.SelectorOpts{TimeLayouts: []string{"02/01/2006"}})
+
+		h, err = cs.TimeVal("schedule.holiday")
+		assert.Nil(t, err)
+		assert.True(t, holiday.Equal(h))
+
+		_, err = cs.TimeVal("timeouts.idle")
+
+		var tme ca.TypeMismatchError
+		assert.True(t, errors.As(err, &tme))
+		assert.EqualValues(t, "time.Time", tme.Expected)
+		assert.EqualValues(t, "number", tme.Actual)
+	}
+}
+
+func TestQuietDurationAndTime(t *testing.T) {
+
+	var failed []string
+
+	qs := ca.NewDeferredErrorQuietSelector(ca.NewDefaultSelector(loadJsonTestFile(t, "timings.json"), true, true), func(path string, err error) {
+		failed = append(failed, path)
+	})
+
+	assert.EqualValues(t, 45*time.Second, qs.DurationVal("timeouts.write"))
+	assert.EqualValues(t, 0, qs.DurationVal("timeouts.invalid"))
+	assert.Len(t, qs.DurationArray("timeouts.list"), 3)
+	assert.Len(t, qs.TimeArray("schedule.dates"), 2)
+	assert.True(t, qs.TimeVal("schedule.holiday").IsZero())
+
+	assert.EqualValues(t, []string{"timeouts.invalid", "schedule.holiday"}, failed)
+}
+
+func TestInjectDurationAndTime(t *testing.T) {
+
+	jsonConf := loadJsonTestFile(t, "timings.json")
+	yamlConf := loadYamlTestFile(t, "timings.yaml")
+
+	for _, node := range []ca.ConfigNode{jsonConf, yamlConf} {
+
+		var to Timeouts
+
+		assert.Nil(t, ca.SetField("Read", "timeouts.read", &to, node))
+		assert.EqualValues(t, 90*time.Second, to.Read)
+
+		assert.Nil(t, ca.SetField("Idle", "timeouts.idle", &to, node))
+		assert.EqualValues(t, 2500*time.Millisecond, *to.Idle)
+
+		assert.NotNil(t, ca.SetField("Write", "timeouts.invalid", &to, node))
+
+		var s Schedule
+
+		assert.Nil(t, ca.SetField("Start", "schedule.start", &s, node))
+		assert.EqualValues(t, 2024, s.Start.Year())
+
+		var timings Timings
+
+		assert.Nil(t, ca.PopulateFromRoot(&timings, node))
+		assert.EqualValues(t, 90*time.Second, timings.Timeouts.Read)
+		assert.EqualValues(t, 45*time.Second, timings.Timeouts.Write)
+		assert.EqualValues(t, 2500*time.Millisecond, *timings.Timeouts.Idle)
+		assert.EqualValues(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, timings.Timeouts.List)
+		assert.EqualValues(t, 9, timings.Schedule.Start.Hour())
+		assert.Len(t, timings.Schedule.Dates, 2)
+
+		// The config itself is not modified
+		assert.EqualValues(t, "1m30s", ca.Value("timeouts.read", node))
+	}
+}